@@ -6,8 +6,8 @@ package alsa
 import "C"
 
 import (
-	"errors"
-	"fmt"
+	"runtime"
+	"sync"
 	"unsafe"
 )
 
@@ -64,37 +64,98 @@ const (
 	SampleFormatU24_3LE = C.SND_PCM_FORMAT_U24_3LE
 	// Unsigned 24bit Big Endian in 3bytes format
 	SampleFormatU24_3BE = C.SND_PCM_FORMAT_U24_3BE
-	/*
-	 SND_PCM_FORMAT_FLOAT_LE 	Float 32 bit Little Endian, Range -1.0 to 1.0
-	 SND_PCM_FORMAT_FLOAT_BE 	Float 32 bit Big Endian, Range -1.0 to 1.0
-	 SND_PCM_FORMAT_FLOAT64_LE 	Float 64 bit Little Endian, Range -1.0 to 1.0
-	 SND_PCM_FORMAT_FLOAT64_BE 	Float 64 bit Big Endian, Range -1.0 to 1.0
-	 SND_PCM_FORMAT_IEC958_SUBFRAME_LE 	IEC-958 Little Endian
-	 SND_PCM_FORMAT_IEC958_SUBFRAME_BE 	IEC-958 Big Endian
-	 SND_PCM_FORMAT_MU_LAW 	Mu-Law
-	 SND_PCM_FORMAT_A_LAW 	A-Law
-	 SND_PCM_FORMAT_IMA_ADPCM 	Ima-ADPCM
-	 SND_PCM_FORMAT_MPEG 	MPEG
-	 SND_PCM_FORMAT_GSM 	GSM
-	 SND_PCM_FORMAT_SPECIAL 	Special
-	 SND_PCM_FORMAT_S20_3LE 	Signed 20bit Little Endian in 3bytes format
-	 SND_PCM_FORMAT_S20_3BE 	Signed 20bit Big Endian in 3bytes format
-	 SND_PCM_FORMAT_U20_3LE 	Unsigned 20bit Little Endian in 3bytes format
-	 SND_PCM_FORMAT_U20_3BE 	Unsigned 20bit Big Endian in 3bytes format
-	 SND_PCM_FORMAT_S18_3LE 	Signed 18bit Little Endian in 3bytes format
-	 SND_PCM_FORMAT_S18_3BE 	Signed 18bit Big Endian in 3bytes format
-	 SND_PCM_FORMAT_U18_3LE 	Unsigned 18bit Little Endian in 3bytes format
-	 SND_PCM_FORMAT_U18_3BE 	Unsigned 18bit Big Endian in 3bytes format
-	 SND_PCM_FORMAT_S16 	Signed 16 bit CPU endian
-	 SND_PCM_FORMAT_U16 	Unsigned 16 bit CPU endian
-	 SND_PCM_FORMAT_S24 	Signed 24 bit CPU endian
-	 SND_PCM_FORMAT_U24 	Unsigned 24 bit CPU endian
-	 SND_PCM_FORMAT_S32 	Signed 32 bit CPU endian
-	 SND_PCM_FORMAT_U32 	Unsigned 32 bit CPU endian
-	 SND_PCM_FORMAT_FLOAT 	Float 32 bit CPU endian
-	 SND_PCM_FORMAT_FLOAT64 	Float 64 bit CPU endian
-	 SND_PCM_FORMAT_IEC958_SUBFRAME 	IEC-958 CPU Endian
-	*/
+	// Float 32 bit Little Endian, Range -1.0 to 1.0
+	SampleFormatFloatLE = C.SND_PCM_FORMAT_FLOAT_LE
+	// Float 32 bit Big Endian, Range -1.0 to 1.0
+	SampleFormatFloatBE = C.SND_PCM_FORMAT_FLOAT_BE
+	// Float 64 bit Little Endian, Range -1.0 to 1.0
+	SampleFormatFloat64LE = C.SND_PCM_FORMAT_FLOAT64_LE
+	// Float 64 bit Big Endian, Range -1.0 to 1.0
+	SampleFormatFloat64BE = C.SND_PCM_FORMAT_FLOAT64_BE
+	// IEC-958 Little Endian
+	SampleFormatIEC958SubframeLE = C.SND_PCM_FORMAT_IEC958_SUBFRAME_LE
+	// IEC-958 Big Endian
+	SampleFormatIEC958SubframeBE = C.SND_PCM_FORMAT_IEC958_SUBFRAME_BE
+	// Mu-Law
+	SampleFormatMuLaw = C.SND_PCM_FORMAT_MU_LAW
+	// A-Law
+	SampleFormatALaw = C.SND_PCM_FORMAT_A_LAW
+	// Ima-ADPCM
+	SampleFormatImaADPCM = C.SND_PCM_FORMAT_IMA_ADPCM
+	// MPEG
+	SampleFormatMPEG = C.SND_PCM_FORMAT_MPEG
+	// GSM
+	SampleFormatGSM = C.SND_PCM_FORMAT_GSM
+	// Signed 20bit Little Endian in 4bytes format, LSB justified
+	SampleFormatS20LE = C.SND_PCM_FORMAT_S20_LE
+	// Signed 20bit Big Endian in 4bytes format, LSB justified
+	SampleFormatS20BE = C.SND_PCM_FORMAT_S20_BE
+	// Unsigned 20bit Little Endian in 4bytes format, LSB justified
+	SampleFormatU20LE = C.SND_PCM_FORMAT_U20_LE
+	// Unsigned 20bit Big Endian in 4bytes format, LSB justified
+	SampleFormatU20BE = C.SND_PCM_FORMAT_U20_BE
+	// Special
+	SampleFormatSpecial = C.SND_PCM_FORMAT_SPECIAL
+	// Signed 20bit Little Endian in 3bytes format
+	SampleFormatS20_3LE = C.SND_PCM_FORMAT_S20_3LE
+	// Signed 20bit Big Endian in 3bytes format
+	SampleFormatS20_3BE = C.SND_PCM_FORMAT_S20_3BE
+	// Unsigned 20bit Little Endian in 3bytes format
+	SampleFormatU20_3LE = C.SND_PCM_FORMAT_U20_3LE
+	// Unsigned 20bit Big Endian in 3bytes format
+	SampleFormatU20_3BE = C.SND_PCM_FORMAT_U20_3BE
+	// Signed 18bit Little Endian in 3bytes format
+	SampleFormatS18_3LE = C.SND_PCM_FORMAT_S18_3LE
+	// Signed 18bit Big Endian in 3bytes format
+	SampleFormatS18_3BE = C.SND_PCM_FORMAT_S18_3BE
+	// Unsigned 18bit Little Endian in 3bytes format
+	SampleFormatU18_3LE = C.SND_PCM_FORMAT_U18_3LE
+	// Unsigned 18bit Big Endian in 3bytes format
+	SampleFormatU18_3BE = C.SND_PCM_FORMAT_U18_3BE
+	// G.723 (ADPCM) 24 kbit/s, 8 samples in 3 bytes
+	SampleFormatG723_24 = C.SND_PCM_FORMAT_G723_24
+	// G.723 (ADPCM) 24 kbit/s, 1 sample in 1 byte
+	SampleFormatG723_24_1B = C.SND_PCM_FORMAT_G723_24_1B
+	// G.723 (ADPCM) 40 kbit/s, 8 samples in 3 bytes
+	SampleFormatG723_40 = C.SND_PCM_FORMAT_G723_40
+	// G.723 (ADPCM) 40 kbit/s, 1 sample in 1 byte
+	SampleFormatG723_40_1B = C.SND_PCM_FORMAT_G723_40_1B
+	// Direct Stream Digital (DSD) in 1-byte samples (x8)
+	SampleFormatDSDU8 = C.SND_PCM_FORMAT_DSD_U8
+	// Direct Stream Digital (DSD) in 2-byte samples (x16), Little Endian
+	SampleFormatDSDU16LE = C.SND_PCM_FORMAT_DSD_U16_LE
+	// Direct Stream Digital (DSD) in 4-byte samples (x32), Little Endian
+	SampleFormatDSDU32LE = C.SND_PCM_FORMAT_DSD_U32_LE
+	// Direct Stream Digital (DSD) in 2-byte samples (x16), Big Endian
+	SampleFormatDSDU16BE = C.SND_PCM_FORMAT_DSD_U16_BE
+	// Direct Stream Digital (DSD) in 4-byte samples (x32), Big Endian
+	SampleFormatDSDU32BE = C.SND_PCM_FORMAT_DSD_U32_BE
+)
+
+// CPU endian sample format aliases.
+const (
+	// Signed 16 bit CPU endian
+	SampleFormatS16 = C.SND_PCM_FORMAT_S16
+	// Unsigned 16 bit CPU endian
+	SampleFormatU16 = C.SND_PCM_FORMAT_U16
+	// Signed 24 bit CPU endian
+	SampleFormatS24 = C.SND_PCM_FORMAT_S24
+	// Unsigned 24 bit CPU endian
+	SampleFormatU24 = C.SND_PCM_FORMAT_U24
+	// Signed 32 bit CPU endian
+	SampleFormatS32 = C.SND_PCM_FORMAT_S32
+	// Unsigned 32 bit CPU endian
+	SampleFormatU32 = C.SND_PCM_FORMAT_U32
+	// Float 32 bit CPU endian
+	SampleFormatFloat = C.SND_PCM_FORMAT_FLOAT
+	// Float 64 bit CPU endian
+	SampleFormatFloat64 = C.SND_PCM_FORMAT_FLOAT64
+	// IEC-958 CPU Endian
+	SampleFormatIEC958Subframe = C.SND_PCM_FORMAT_IEC958_SUBFRAME
+	// Signed 20bit in 4bytes format, LSB justified, CPU Endian
+	SampleFormatS20 = C.SND_PCM_FORMAT_S20
+	// Unsigned 20bit in 4bytes format, LSB justified, CPU Endian
+	SampleFormatU20 = C.SND_PCM_FORMAT_U20
 )
 
 // Open mode constants.
@@ -107,6 +168,11 @@ const (
 // Handle represents ALSA stream handler.
 type Handle struct {
 	cHandle *C.snd_pcm_t
+	// Device name and stream direction the handle was opened with.
+	device     string
+	streamType StreamType
+	// Open mode flags.
+	mode int
 	// Used samples format (size, endianness, signed).
 	SampleFormat SampleFormat
 	// Sample rate in Hz. Usual 44100.
@@ -115,13 +181,31 @@ type Handle struct {
 	Channels int
 	// The interval between interrupts from the hardware
 	Periods int
+	// Size of period in frames
+	PeriodSize int
 	// Size of buffer in frames
 	Buffersize int
+	// Access type. Zero value is RW interleaved.
+	Access Access
+	// Xrun and suspend handling of I/O methods.
+	Recovery RecoveryMode
+	// Called after xrun or suspend recovery in RecoveryNotify mode with
+	// the error and estimated number of lost frames.
+	OnXrun func(err error, framesLost int)
+	xruns  xrunCounters
+	// Idle pollers of the context aware methods, guarded by pollerLock.
+	// Each wait takes its own, so a wakeup reaches only its context.
+	pollers    []*Poller
+	pollerLock sync.Mutex
+	// Running playback callback engine, guarded by engineLock.
+	engine     *callbackEngine
+	engineLock sync.Mutex
 }
 
 // New returns newly initialized ALSA handler.
 func New() *Handle {
 	handler := new(Handle)
+	handler.Access = AccessRWInterleaved
 	return handler
 }
 
@@ -135,100 +219,117 @@ func (handle *Handle) Open(device string, streamType StreamType, mode int) error
 		C.int(mode))
 
 	if err < 0 {
-		return errors.New(fmt.Sprintf("Cannot open audio device '%s'. %s",
-			device, strError(err)))
+		return newError("open", device, streamType, err)
 	}
 
+	handle.device = device
+	handle.streamType = streamType
+	handle.mode = mode
+
 	return nil
 }
 
-// ApplyHwParams changes ALSA hardware parameters for the current stream.
-func (handle *Handle) ApplyHwParams() error {
-	var cHwParams *C.snd_pcm_hw_params_t
+// OpenWithConfig opens ALSA device defined by configText in asoundrc
+// syntax, e.g. "pcm.out { type null }". The definitions are added to a
+// copy of the global configuration, so they may refer to its devices.
+func (handle *Handle) OpenWithConfig(device, configText string, streamType StreamType, mode int) error {
+	config, err := GlobalConfig()
+	if err != nil {
+		return err
+	}
+	defer config.Close()
 
-	err := C.snd_pcm_hw_params_malloc(&cHwParams)
-	if err < 0 {
-		return errors.New(fmt.Sprintf("Cannot allocate hardware parameter structure. %s",
-			strError(err)))
+	if err = config.Load(configText); err != nil {
+		return err
 	}
 
-	err = C.snd_pcm_hw_params_any(handle.cHandle, cHwParams)
+	return handle.OpenConfig(device, config, streamType, mode)
+}
+
+// OpenConfig opens ALSA device looked up in the configuration tree instead
+// of the global configuration.
+func (handle *Handle) OpenConfig(device string, config *Config, streamType StreamType, mode int) error {
+	cDevice := C.CString(device)
+	defer C.free(unsafe.Pointer(cDevice))
+
+	err := C.snd_pcm_open_lconf(&(handle.cHandle), cDevice,
+		C.snd_pcm_stream_t(streamType),
+		C.int(mode), config.cConfig)
+
 	if err < 0 {
-		return errors.New(fmt.Sprintf("Cannot initialize hardware parameter structure. %s",
-			strError(err)))
+		return newError("open", device, streamType, err)
 	}
 
-	err = C.snd_pcm_hw_params_set_access(handle.cHandle, cHwParams, C.SND_PCM_ACCESS_RW_INTERLEAVED)
-	if err < 0 {
-		return errors.New(fmt.Sprintf("Cannot set access type. %s",
-			strError(err)))
+	handle.device = device
+	handle.streamType = streamType
+	handle.mode = mode
+
+	return nil
+}
+
+// newError returns Error for the failed operation on the handle stream.
+func (handle *Handle) newError(op string, code C.int) error {
+	return newError(op, handle.device, handle.streamType, code)
+}
+
+// ApplyHwParams changes ALSA hardware parameters for the current stream.
+// Sample rate, periods and buffer size are negotiated with the device and
+// the values actually granted are written back to the handle.
+func (handle *Handle) ApplyHwParams() error {
+	params, err := handle.HwParams()
+	if err != nil {
+		return err
 	}
+	defer params.Free()
 
-	err = C.snd_pcm_hw_params_set_format(handle.cHandle, cHwParams, C.snd_pcm_format_t(handle.SampleFormat))
-	if err < 0 {
-		return errors.New(fmt.Sprintf("Cannot set sample format. %s",
-			strError(err)))
+	err = params.SetAccess(handle.Access)
+	if err != nil {
+		return err
 	}
 
-	var cSampleRate C.uint = C.uint(handle.SampleRate)
-	err = C.snd_pcm_hw_params_set_rate_near(handle.cHandle, cHwParams, &cSampleRate, nil)
-	if err < 0 {
-		return errors.New(fmt.Sprintf("Cannot set sample rate. %s",
-			strError(err)))
+	err = params.SetFormat(handle.SampleFormat)
+	if err != nil {
+		return err
 	}
 
-	err = C.snd_pcm_hw_params_set_channels(handle.cHandle, cHwParams, C.uint(handle.Channels))
-	if err < 0 {
-		return errors.New(fmt.Sprintf("Cannot set number of channels. %s",
-			strError(err)))
+	_, err = params.SetRateNear(handle.SampleRate)
+	if err != nil {
+		return err
+	}
+
+	err = params.SetChannels(handle.Channels)
+	if err != nil {
+		return err
 	}
 
 	if handle.Periods > 0 {
 		// Set number of periods. Periods used to be called fragments.
-		/*err = C.snd_pcm_hw_params_set_periods(handle.cHandle, cHwParams, C.uint(handle.Periods), 0)
-		if err < 0 {
-			return os.NewError(fmt.Sprintf("Cannot set number of periods. %s",
-				strError(err)))
-		}*/
-
-		var cPeriods C.uint = C.uint(handle.Periods)
-		var cDir C.int = 0 // Exact value is <,=,> the returned one following dir (-1,0,1)
-		err = C.snd_pcm_hw_params_set_periods_near(handle.cHandle, cHwParams, &cPeriods, &cDir)
-		if err < 0 {
-			return errors.New(fmt.Sprintf("Cannot set number of periods. %s",
-				strError(err)))
+		_, err = params.SetPeriodsNear(handle.Periods)
+		if err != nil {
+			return err
+		}
+	}
+
+	if handle.PeriodSize > 0 {
+		_, err = params.SetPeriodSizeNear(handle.PeriodSize)
+		if err != nil {
+			return err
 		}
 	}
 
 	if handle.Buffersize > 0 {
 		// Set buffer size (in frames). The resulting latency is given by
 		// latency = periodsize * periods / (rate * bytes_per_frame)
-		/*err = C.snd_pcm_hw_params_set_buffer_size(handle.cHandle, cHwParams, _Ctypedef_snd_pcm_uframes_t(handle.Buffersize))
-		if err < 0 {
-			return os.NewError(fmt.Sprintf("Cannot set buffersize. %s",
-				strError(err)))
-		}*/
-
-		var cBuffersize C.snd_pcm_uframes_t = C.snd_pcm_uframes_t(handle.Buffersize)
-		err = C.snd_pcm_hw_params_set_buffer_size_near(handle.cHandle, cHwParams, &cBuffersize)
-		if err < 0 {
-			return errors.New(fmt.Sprintf("Cannot set buffersize. %s",
-				strError(err)))
+		_, err = params.SetBufferSizeNear(handle.Buffersize)
+		if err != nil {
+			return err
 		}
 	}
 
 	// Drain current data and make sure we aren't underrun.
 	C.snd_pcm_drain(handle.cHandle)
 
-	err = C.snd_pcm_hw_params(handle.cHandle, cHwParams)
-	if err < 0 {
-		return errors.New(fmt.Sprintf("Cannot set hardware parameters. %s",
-			strError(err)))
-	}
-
-	C.snd_pcm_hw_params_free(cHwParams)
-
-	return nil
+	return params.Apply()
 }
 
 // Drain stream. For playback wait for all pending frames to be played and
@@ -237,8 +338,7 @@ func (handle *Handle) Drain() error {
 
 	err := C.snd_pcm_drain(handle.cHandle)
 	if err < 0 {
-		return errors.New(fmt.Sprintf("Cannot drain stream. %s",
-			strError(err)))
+		return handle.newError("drain", err)
 	}
 	return nil
 
@@ -250,8 +350,7 @@ func (handle *Handle) Drop() error {
 
 	err := C.snd_pcm_drop(handle.cHandle)
 	if err < 0 {
-		return errors.New(fmt.Sprintf("Cannot drop stream. %s",
-			strError(err)))
+		return handle.newError("drop", err)
 	}
 	return nil
 
@@ -259,39 +358,24 @@ func (handle *Handle) Drop() error {
 
 // MaxSampleRate returns the maximum samplerate possible for the device
 func (handle *Handle) MaxSampleRate() (int, error) {
-
-	var cHwParams *C.snd_pcm_hw_params_t
-
-	err := C.snd_pcm_hw_params_malloc(&cHwParams)
-	if err < 0 {
-		return 0, errors.New(fmt.Sprintf("Cannot allocate hardware parameter structure. %s",
-			strError(err)))
-	}
-
-	err = C.snd_pcm_hw_params_any(handle.cHandle, cHwParams)
-	if err < 0 {
-		return 0, errors.New(fmt.Sprintf("Cannot initialize hardware parameter structure. %s",
-			strError(err)))
+	params, err := handle.HwParams()
+	if err != nil {
+		return 0, err
 	}
+	defer params.Free()
 
-	err = C.snd_pcm_hw_params_set_rate_resample(handle.cHandle, cHwParams, 0)
-	if err < 0 {
-		return 0, errors.New(fmt.Sprintf("Cannot restrict configuration space to contain only real hardware rates. %s",
-			strError(err)))
+	// Restrict configuration space to contain only real hardware rates.
+	err = params.SetRateResample(false)
+	if err != nil {
+		return 0, err
 	}
 
-	var maxRate C.uint
-	var dir C.int
-
-	err = C.snd_pcm_hw_params_get_rate_max(cHwParams, &maxRate, &dir)
-	if err < 0 {
-		return 0, errors.New(fmt.Sprintf("Retrieving maximum samplerate failed. %s", err))
+	_, maxRate, err := params.RateRange()
+	if err != nil {
+		return 0, err
 	}
 
-	C.snd_pcm_hw_params_free(cHwParams)
-
-	return int(maxRate), nil
-
+	return maxRate, nil
 }
 
 // Delay returns the numbers of frames between the time that a frame that
@@ -300,7 +384,7 @@ func (handle *Handle) Delay() (int, error) {
 	var delay C.snd_pcm_sframes_t
 	err := C.snd_pcm_delay(handle.cHandle, &delay)
 	if err < 0 {
-		return 0, errors.New(fmt.Sprintf("Retrieving delay failed. %s", strError(C.int(delay))))
+		return 0, handle.newError("get delay", err)
 	}
 
 	return int(C.int(delay)), nil
@@ -313,7 +397,7 @@ func (handle *Handle) SkipFrames(frames int) (int, error) {
 	var framesForwardable C.snd_pcm_sframes_t
 	framesForwardable = C.snd_pcm_forwardable(handle.cHandle)
 	if framesForwardable < 0 {
-		return 0, errors.New(fmt.Sprintf("Retrieving forwardable frames failed. %s", strError(C.int(framesForwardable))))
+		return 0, handle.newError("get forwardable frames", C.int(framesForwardable))
 	}
 
 	if int(C.int(framesForwardable)) < frames {
@@ -324,7 +408,7 @@ func (handle *Handle) SkipFrames(frames int) (int, error) {
 	var framesForwarded C.snd_pcm_sframes_t
 	framesForwarded = C.snd_pcm_forward(handle.cHandle, C.snd_pcm_uframes_t(frames))
 	if framesForwarded < 0 {
-		return 0, errors.New(fmt.Sprintf("Cannot forward frames. %s", strError(C.int(framesForwarded))))
+		return 0, handle.newError("forward frames", C.int(framesForwarded))
 	}
 
 	return int(C.int(framesForwarded)), nil
@@ -334,9 +418,9 @@ func (handle *Handle) SkipFrames(frames int) (int, error) {
 // delay time is runs out.
 // true ok value means that PCM stream is ready for I/O, false -- timeout occured.
 func (handle *Handle) Wait(maxDelay int) (ok bool, err error) {
-	res, err := C.snd_pcm_wait(handle.cHandle, C.int(maxDelay))
-	if err != nil {
-		return false, errors.New(fmt.Sprintf("Pool failed. %s", err))
+	res := C.snd_pcm_wait(handle.cHandle, C.int(maxDelay))
+	if res < 0 {
+		return false, handle.newError("wait", res)
 	}
 
 	return res > 0, nil
@@ -346,59 +430,158 @@ func (handle *Handle) Wait(maxDelay int) (ok bool, err error) {
 func (handle *Handle) AvailUpdate() (freeBytes int, err error) {
 	frames := C.snd_pcm_avail_update(handle.cHandle)
 	if frames < 0 {
-		return 0, errors.New(fmt.Sprintf("Retriving free buffer size failed. %s", strError(C.int(frames))))
+		return 0, handle.newError("get available frames", C.int(frames))
 	}
 
 	return int(frames) * handle.FrameSize(), nil
 }
 
-// Write writes given PCM data.
+// Write writes given PCM data. Trailing partial frame is not written.
 // Returns wrote value is total bytes was written.
 func (handle *Handle) Write(buf []byte) (wrote int, err error) {
+	frames, err := handle.WriteFrames(buf)
 
-	if handle.Channels == 0 {
-		return 0, errors.New(fmt.Sprintf("Channel count is zero"))
+	return frames * handle.FrameSize(), err
+}
+
+// WriteFrames writes whole frames of given interleaved PCM data.
+// Returns number of frames written.
+func (handle *Handle) WriteFrames(buf []byte) (frames int, err error) {
+	frames, err = handle.interleavedFrames(buf)
+	if err != nil || frames == 0 {
+		return 0, err
 	}
 
-	frames := len(buf) / handle.SampleSize() / handle.Channels
-	w := C.snd_pcm_writei(handle.cHandle, unsafe.Pointer(&buf[0]), C.snd_pcm_uframes_t(frames))
+	frameSize := handle.FrameSize()
+
+	return handle.transfer("write", frames, func(offset, frames int) C.snd_pcm_sframes_t {
+		return C.snd_pcm_writei(handle.cHandle, unsafe.Pointer(&buf[offset*frameSize]), C.snd_pcm_uframes_t(frames))
+	}, nil)
+}
 
-	// Underrun? Retry.
-	if w == -C.EPIPE {
-		C.snd_pcm_prepare(handle.cHandle)
-		w = C.snd_pcm_writei(handle.cHandle, unsafe.Pointer(&buf[0]), C.snd_pcm_uframes_t(frames))
+// Read reads PCM data from microphone device into whole frames of buf,
+// following io.Reader contract.
+// Return read value in number of bytes read.
+func (handle *Handle) Read(buf []byte) (n int, err error) {
+	frames, err := handle.ReadFrames(buf)
+
+	return frames * handle.FrameSize(), err
+}
+
+// ReadFrames reads interleaved PCM data into whole frames of buf.
+// Returns number of frames read.
+func (handle *Handle) ReadFrames(buf []byte) (frames int, err error) {
+	frames, err = handle.interleavedFrames(buf)
+	if err != nil || frames == 0 {
+		return 0, err
 	}
 
-	if w < 0 {
-		return 0, errors.New(fmt.Sprintf("Write failed. %s", strError(C.int(w))))
+	frameSize := handle.FrameSize()
+
+	return handle.transfer("read", frames, func(offset, frames int) C.snd_pcm_sframes_t {
+		return C.snd_pcm_readi(handle.cHandle, unsafe.Pointer(&buf[offset*frameSize]), C.snd_pcm_uframes_t(frames))
+	}, func(offset, frames int) {
+		handle.fillSilence(buf[offset*frameSize : (offset+frames)*frameSize])
+	})
+}
+
+// interleavedFrames returns number of whole frames buf can hold.
+func (handle *Handle) interleavedFrames(buf []byte) (int, error) {
+	frameSize := handle.FrameSize()
+	if frameSize == 0 {
+		return 0, ErrNotConfigured
 	}
 
-	wrote = int(w)
-	wrote *= handle.FrameSize()
+	if len(buf) > 0 && len(buf) < frameSize {
+		return 0, ErrInvalidBuffer
+	}
 
-	return wrote, nil
+	return len(buf) / frameSize, nil
 }
 
-// Read reads PCM data from microphone device
-// Return read value in number of bytes read.
-func (handle *Handle) Read(buf []byte) (n int, err error) {
-	count := len(buf)
+// WriteNonInterleaved writes PCM data kept in one buffer per channel.
+// Stream must use AccessRWNonInterleaved access.
+// Returns number of frames written to every channel.
+func (handle *Handle) WriteNonInterleaved(bufs [][]byte) (frames int, err error) {
+	frames, err = handle.planarFrames(bufs)
+	if err != nil || frames == 0 {
+		return 0, err
+	}
+
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+	sampleSize := handle.SampleSize()
+
+	return handle.transfer("write", frames, func(offset, frames int) C.snd_pcm_sframes_t {
+		ptrs := planarPointers(bufs, offset*sampleSize, &pinner)
+		return C.snd_pcm_writen(handle.cHandle, &ptrs[0], C.snd_pcm_uframes_t(frames))
+	}, nil)
+}
 
-	buf_p := unsafe.Pointer(&buf[0])
-	n_c := C.snd_pcm_readi(handle.cHandle, buf_p, C.snd_pcm_uframes_t(count))
-	n = int(n_c)
-	if n < 0 {
-		err = errors.New(fmt.Sprintf("Read error: %s", n))
-		return n, err
+// ReadNonInterleaved reads PCM data into one buffer per channel.
+// Stream must use AccessRWNonInterleaved access.
+// Returns number of frames read into every channel.
+func (handle *Handle) ReadNonInterleaved(bufs [][]byte) (frames int, err error) {
+	frames, err = handle.planarFrames(bufs)
+	if err != nil || frames == 0 {
+		return 0, err
 	}
-	return n, nil
+
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+	sampleSize := handle.SampleSize()
+
+	return handle.transfer("read", frames, func(offset, frames int) C.snd_pcm_sframes_t {
+		ptrs := planarPointers(bufs, offset*sampleSize, &pinner)
+		return C.snd_pcm_readn(handle.cHandle, &ptrs[0], C.snd_pcm_uframes_t(frames))
+	}, func(offset, frames int) {
+		for _, buf := range bufs {
+			handle.fillSilence(buf[offset*sampleSize : (offset+frames)*sampleSize])
+		}
+	})
+}
+
+// planarFrames validates per channel buffers and returns their length in frames.
+func (handle *Handle) planarFrames(bufs [][]byte) (int, error) {
+	if handle.FrameSize() == 0 {
+		return 0, ErrNotConfigured
+	}
+
+	if len(bufs) != handle.Channels {
+		return 0, ErrInvalidBuffer
+	}
+
+	size := len(bufs[0])
+	for _, buf := range bufs {
+		if len(buf) != size {
+			return 0, ErrInvalidBuffer
+		}
+	}
+
+	if size%handle.SampleSize() != 0 {
+		return 0, ErrInvalidBuffer
+	}
+
+	return size / handle.SampleSize(), nil
+}
+
+// planarPointers returns pinned pointers to the channel buffers at byte
+// offset suitable for passing to snd_pcm_writen/snd_pcm_readn.
+func planarPointers(bufs [][]byte, offset int, pinner *runtime.Pinner) []unsafe.Pointer {
+	ptrs := make([]unsafe.Pointer, len(bufs))
+	for i, buf := range bufs {
+		pinner.Pin(&buf[0])
+		ptrs[i] = unsafe.Pointer(&buf[offset])
+	}
+
+	return ptrs
 }
 
 // Pause PCM.
 func (handle *Handle) Pause() error {
 	err := C.snd_pcm_pause(handle.cHandle, 1)
 	if err != 0 {
-		return errors.New(fmt.Sprintf("Pause failed. %s", strError(err)))
+		return handle.newError("pause", err)
 	}
 
 	return nil
@@ -408,7 +591,7 @@ func (handle *Handle) Pause() error {
 func (handle *Handle) Unpause() error {
 	err := C.snd_pcm_pause(handle.cHandle, 0)
 	if err != 0 {
-		return errors.New(fmt.Sprintf("Unpause failed. %s", strError(err)))
+		return handle.newError("unpause", err)
 	}
 
 	return nil
@@ -416,38 +599,25 @@ func (handle *Handle) Unpause() error {
 
 // Close closes stream and release the handler.
 func (handle *Handle) Close() {
+	handle.StopCallback()
+
 	C.snd_pcm_close(handle.cHandle)
+
+	handle.pollerLock.Lock()
+	for _, poller := range handle.pollers {
+		poller.Close()
+	}
+	handle.pollers = nil
+	handle.pollerLock.Unlock()
 }
 
-// SampleSize returns one sample size in bytes.
+// SampleSize returns one sample size in bytes. Zero for formats which
+// do not store a sample in whole bytes.
 func (handle *Handle) SampleSize() int {
-	switch handle.SampleFormat {
-	case SampleFormatS8, SampleFormatU8:
-		return 1
-	case SampleFormatS16LE, SampleFormatS16BE,
-		SampleFormatU16LE, SampleFormatU16BE:
-		return 2
-	case SampleFormatS24_3LE, SampleFormatS24_3BE,
-		SampleFormatU24_3LE, SampleFormatU24_3BE:
-		return 3
-	case SampleFormatS24LE, SampleFormatS24BE,
-		SampleFormatU24LE, SampleFormatU24BE,
-		SampleFormatS32LE, SampleFormatS32BE,
-		SampleFormatU32LE, SampleFormatU32BE:
-		return 4
-	}
-
-	return 1
+	return handle.SampleFormat.PhysicalWidth() / 8
 }
 
 // FrameSize returns size of one frame in bytes.
 func (handle *Handle) FrameSize() int {
 	return handle.SampleSize() * handle.Channels
 }
-
-// strError retruns string description of ALSA error by its code.
-func strError(err C.int) string {
-	cErrMsg := C.snd_strerror(err)
-
-	return C.GoString(cErrMsg)
-}
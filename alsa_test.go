@@ -55,12 +55,97 @@ func TestCapture(t *testing.T) {
 		t.Fatalf("Writei failed. %s", err)
 	}
 	if len(buf) != n {
-		t.Errorf("Could not read all data, Read %s (expected %s)", n, buflen)
+		t.Errorf("Could not read all data, Read %d (expected %d)", n, buflen)
 	}
 	handle.Close()
 
 }
 
+func TestCaptureFrames(t *testing.T) {
+	handle := New()
+	err := handle.Open("default", StreamTypeCapture, ModeBlock)
+	if err != nil {
+		t.Fatalf("Open failed. %s", err)
+	}
+
+	handle.SampleFormat = SampleFormatS16LE
+	handle.SampleRate = 44100
+	handle.Channels = 2
+	err = handle.ApplyHwParams()
+	if err != nil {
+		t.Fatalf("SetHwParams failed. %s", err)
+	}
+
+	// Not a whole number of frames: the tail must stay untouched.
+	buf := make([]byte, 1026)
+	buf[1024] = 0xAA
+	n, err := handle.Read(buf)
+	if err != nil {
+		t.Fatalf("Read failed. %s", err)
+	}
+	if n != 1024 {
+		t.Errorf("Could not read all frames, Read %d (expected 1024)", n)
+	}
+	if buf[1024] != 0xAA {
+		t.Errorf("Read wrote past the last whole frame")
+	}
+
+	n, err = handle.Read(nil)
+	if n != 0 || err != nil {
+		t.Errorf("Read of empty buffer returned %d, %v", n, err)
+	}
+
+	handle.Close()
+}
+
+func TestWriteNonInterleaved(t *testing.T) {
+	handle := New()
+	err := handle.Open("default", StreamTypePlayback, ModeBlock)
+	if err != nil {
+		t.Fatalf("Open failed. %s", err)
+	}
+
+	handle.SampleFormat = SampleFormatS16LE
+	handle.SampleRate = 44100
+	handle.Channels = 2
+	handle.Access = AccessRWNonInterleaved
+	err = handle.ApplyHwParams()
+	if err != nil {
+		t.Fatalf("SetHwParams failed. %s", err)
+	}
+
+	left := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	right := []byte{11, 12, 13, 14, 15, 16, 17, 18, 19, 20}
+
+	frames, err := handle.WriteNonInterleaved([][]byte{left, right})
+	if err != nil {
+		t.Fatalf("WriteNonInterleaved failed. %s", err)
+	}
+	if frames != 5 {
+		t.Errorf("Did not write all the frames (Wrote %d, expected 5)", frames)
+	}
+
+	handle.Close()
+}
+
+func TestNonInterleavedValidation(t *testing.T) {
+	handle := New()
+	handle.SampleFormat = SampleFormatS16LE
+	handle.Channels = 2
+
+	tests := [][][]byte{
+		{make([]byte, 4)},
+		{make([]byte, 4), make([]byte, 6)},
+		{make([]byte, 3), make([]byte, 3)},
+	}
+	for _, bufs := range tests {
+		_, err := handle.WriteNonInterleaved(bufs)
+		if err != ErrInvalidBuffer {
+			t.Errorf("Invalid buffers %v accepted. %v", bufs, err)
+		}
+	}
+}
+
 func BenchmarkRead(b *testing.B) {
 	handle := New()
 	err := handle.Open("default", StreamTypeCapture, ModeBlock)
@@ -128,7 +213,7 @@ func ExampleHandle_Read() {
 		fmt.Printf("Read failed. %s", err)
 	}
 	if n != len(buf) {
-		fmt.Printf("Could not read all data (Read %i, expected %i)", n, len(buf))
+		fmt.Printf("Could not read all data (Read %d, expected %d)", n, len(buf))
 	}
 	handle.Close()
 }
@@ -152,7 +237,7 @@ func ExampleHandle_Write() {
 		fmt.Printf("Write failed %s", err)
 	}
 	if n != len(buf) {
-		fmt.Printf("Did not write all data (Wrote %s, expected %s)", n, len(buf))
+		fmt.Printf("Did not write all data (Wrote %d, expected %d)", n, len(buf))
 	}
 	handle.Close()
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	alsa "github.com/thinkontrol/alsa-cgo"
 )
@@ -32,9 +33,33 @@ func printParameters(streamType alsa.StreamType, filename string, handle *alsa.H
 
 }
 
+func listDevices(streamType alsa.StreamType) {
+	cards, err := alsa.ListCards()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "List cards failed. %v\n", err)
+	}
+	for _, card := range cards {
+		fmt.Printf("card %v: %v [%v]\n", card.Index, card.Name, card.LongName)
+	}
+
+	devices, err := alsa.ListDevices(streamType)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "List devices failed. %v\n", err)
+		return
+	}
+	for _, device := range devices {
+		fmt.Printf("%v\n", device.Name)
+		for _, line := range strings.Split(device.Description, "\n") {
+			fmt.Printf("    %v\n", line)
+		}
+	}
+}
+
 func main() {
 	rate := flag.Int("rate", 8000, "Sample rate (in Hz) of device")
 	channels := flag.Int("channels", 1, "Number of channels")
+	device := flag.String("device", "default", "PCM device name to open.")
+	list := flag.Bool("list", false, "List cards and PCM devices of the stream type")
 	help := flag.Bool("help", false, "help")
 	streamTypeString := flag.String("stream", "default",
 		"The type of the stream. Can be \"play\" or \"record\". \"default\" depends on name of the binary.")
@@ -65,6 +90,11 @@ func main() {
 		}
 	}
 
+	if *list {
+		listDevices(streamType)
+		return
+	}
+
 	// Defining the file to use
 	var file *os.File
 	var err error
@@ -88,7 +118,7 @@ func main() {
 				file, err = os.Open(*filename)
 			}
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error opening file", err)
+				fmt.Fprintf(os.Stderr, "Error opening file. %v\n", err)
 				return
 			}
 		}
@@ -96,7 +126,7 @@ func main() {
 
 	// Opening handle
 	handle := alsa.New()
-	err = handle.Open("default", streamType, alsa.ModeBlock)
+	err = handle.Open(*device, streamType, alsa.ModeBlock)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Open failed. %v", err)
 	}
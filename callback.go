@@ -0,0 +1,194 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+import "C"
+
+import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+)
+
+// Callback fills out with frames frames of interleaved PCM data.
+// out is reused between calls and must not be retained.
+type Callback func(out []byte, frames int)
+
+// callbackEngine is the state of a running playback callback engine.
+type callbackEngine struct {
+	poller  *Poller
+	stopped atomic.Bool
+	done    chan struct{}
+	// Error which stopped the engine.
+	err error
+}
+
+// StartCallback starts pulling playback data from fill. A dedicated
+// goroutine locked to an OS thread waits for the device wakeups (see
+// SwParams.SetAvailMin and SetPeriodEvent) and calls fill for exactly the
+// frames free in the buffer. Xruns are handled according to the handle
+// Recovery mode and counted in XrunStats. Hardware parameters must be
+// applied first; the engine runs till StopCallback or Close.
+func (handle *Handle) StartCallback(fill Callback) error {
+	if handle.streamType != StreamTypePlayback {
+		return handle.newError("start callback", -C.EINVAL)
+	}
+	if handle.FrameSize() == 0 || handle.Buffersize == 0 {
+		return ErrNotConfigured
+	}
+
+	handle.engineLock.Lock()
+	defer handle.engineLock.Unlock()
+
+	if handle.engine != nil {
+		if !handle.engine.finished() {
+			return handle.newError("start callback", -C.EBUSY)
+		}
+		// Engine stopped by an error is replaced.
+		handle.engine.poller.Close()
+		handle.engine = nil
+	}
+
+	poller, err := NewPoller()
+	if err != nil {
+		return err
+	}
+
+	engine := &callbackEngine{
+		poller: poller,
+		done:   make(chan struct{}),
+	}
+	handle.engine = engine
+
+	started := make(chan error)
+	go handle.runCallback(engine, fill, started)
+
+	err = <-started
+	if err != nil {
+		<-engine.done
+		handle.engine = nil
+		poller.Close()
+	}
+
+	return err
+}
+
+// StopCallback stops the playback callback engine and drops pending frames.
+// Returns the error which stopped the engine earlier, if any.
+func (handle *Handle) StopCallback() error {
+	handle.engineLock.Lock()
+	defer handle.engineLock.Unlock()
+
+	engine := handle.engine
+	if engine == nil {
+		return nil
+	}
+	handle.engine = nil
+
+	engine.stop()
+	C.snd_pcm_drop(handle.cHandle)
+
+	return engine.err
+}
+
+// finished reports whether the engine goroutine has exited.
+func (engine *callbackEngine) finished() bool {
+	select {
+	case <-engine.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop stops the engine goroutine and releases its poller.
+func (engine *callbackEngine) stop() {
+	engine.stopped.Store(true)
+	engine.poller.Wake()
+	<-engine.done
+
+	engine.poller.Close()
+}
+
+// runCallback is the engine goroutine.
+func (handle *Handle) runCallback(engine *callbackEngine, fill Callback, started chan<- error) {
+	defer close(engine.done)
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	restore, err := handle.enterNonblock()
+	if err != nil {
+		started <- err
+		return
+	}
+	defer restore()
+
+	// Stream is left stopped by StopCallback or a failed engine.
+	if handle.State() != PCMStatePrepared {
+		if err = handle.Prepare(); err != nil {
+			started <- err
+			return
+		}
+	}
+
+	frameSize := handle.FrameSize()
+	buf := make([]byte, handle.Buffersize*frameSize)
+
+	// Prime the whole buffer, so the stream starts with full latency.
+	fill(buf, handle.Buffersize)
+	written, err := handle.WriteFrames(buf)
+	if err != nil && !errors.Is(err, ErrWouldBlock) {
+		started <- err
+		return
+	}
+	// Filled frames not taken by the device yet, written before fill is
+	// called again.
+	pending := buf[written*frameSize:]
+	if handle.State() == PCMStatePrepared {
+		if err = handle.Start(); err != nil {
+			started <- err
+			return
+		}
+	}
+	started <- nil
+
+	for !engine.stopped.Load() {
+		_, err = engine.poller.Wait([]*Handle{handle}, -1)
+		if err == ErrWoken {
+			continue
+		}
+		if err != nil {
+			engine.err = err
+			return
+		}
+
+		avail := C.snd_pcm_avail_update(handle.cHandle)
+		if avail < 0 {
+			if _, err = handle.recoverXrun("write", C.int(avail)); err != nil {
+				engine.err = err
+				return
+			}
+			continue
+		}
+
+		frames := int(avail)
+		if frames == 0 {
+			continue
+		}
+		if frames > handle.Buffersize {
+			frames = handle.Buffersize
+		}
+
+		if len(pending) == 0 {
+			pending = buf[:frames*frameSize]
+			fill(pending, frames)
+		}
+
+		written, err = handle.WriteFrames(pending)
+		pending = pending[written*frameSize:]
+		if err != nil && !errors.Is(err, ErrWouldBlock) {
+			engine.err = err
+			return
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package alsa
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStartCallback(t *testing.T) {
+	handle := New()
+	err := handle.Open("default", StreamTypePlayback, ModeBlock)
+	if err != nil {
+		t.Fatalf("Open failed. %s", err)
+	}
+	defer handle.Close()
+
+	handle.SampleFormat = SampleFormatS16LE
+	handle.SampleRate = 44100
+	handle.Channels = 2
+	err = handle.ApplyHwParams()
+	if err != nil {
+		t.Fatalf("ApplyHwParams failed. %s", err)
+	}
+
+	var calls, frames atomic.Int64
+	err = handle.StartCallback(func(out []byte, n int) {
+		if len(out) != n*handle.FrameSize() {
+			t.Errorf("Buffer of %d bytes for %d frames", len(out), n)
+		}
+		calls.Add(1)
+		frames.Add(int64(n))
+	})
+	if err != nil {
+		t.Fatalf("StartCallback failed. %s", err)
+	}
+
+	if err = handle.StartCallback(func([]byte, int) {}); err == nil {
+		t.Errorf("Second StartCallback succeeded")
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	if err = handle.StopCallback(); err != nil {
+		t.Fatalf("StopCallback failed. %s", err)
+	}
+
+	// Priming call plus period wakeups.
+	if calls.Load() < 2 {
+		t.Errorf("Callback called %d times", calls.Load())
+	}
+	if frames.Load() <= int64(handle.Buffersize) {
+		t.Errorf("Callback filled only %d frames", frames.Load())
+	}
+
+	// Stopped engine can be started again.
+	if err = handle.StartCallback(func([]byte, int) {}); err != nil {
+		t.Fatalf("Restarting StartCallback failed. %s", err)
+	}
+	if err = handle.StopCallback(); err != nil {
+		t.Fatalf("StopCallback failed. %s", err)
+	}
+}
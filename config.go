@@ -0,0 +1,279 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Config node type.
+type ConfigType C.snd_config_type_t
+
+// Config node type constants.
+const (
+	ConfigTypeInteger   = C.SND_CONFIG_TYPE_INTEGER
+	ConfigTypeInteger64 = C.SND_CONFIG_TYPE_INTEGER64
+	ConfigTypeReal      = C.SND_CONFIG_TYPE_REAL
+	ConfigTypeString    = C.SND_CONFIG_TYPE_STRING
+	ConfigTypePointer   = C.SND_CONFIG_TYPE_POINTER
+	// Node with children
+	ConfigTypeCompound = C.SND_CONFIG_TYPE_COMPOUND
+)
+
+// String returns the config type name.
+func (configType ConfigType) String() string {
+	switch configType {
+	case ConfigTypeInteger:
+		return "integer"
+	case ConfigTypeInteger64:
+		return "integer64"
+	case ConfigTypeReal:
+		return "real"
+	case ConfigTypeString:
+		return "string"
+	case ConfigTypePointer:
+		return "pointer"
+	case ConfigTypeCompound:
+		return "compound"
+	}
+
+	return fmt.Sprintf("unknown (%d)", int(configType))
+}
+
+// Config is a node of an ALSA configuration tree. Nodes returned by Search
+// and Children belong to the tree and are valid till it is closed.
+type Config struct {
+	cConfig *C.snd_config_t
+	// Tree is owned and deleted on Close.
+	top bool
+}
+
+// ParseConfig parses configuration text in asoundrc syntax into a new
+// tree.
+func ParseConfig(text string) (*Config, error) {
+	config := &Config{top: true}
+	err := C.snd_config_top(&config.cConfig)
+	if err < 0 {
+		return nil, newDeviceError("create config", "", err)
+	}
+
+	if err := config.Load(text); err != nil {
+		config.Close()
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// GlobalConfig returns a copy of the global configuration loaded from
+// alsa.conf and asoundrc files. Changes do not affect other clients.
+func GlobalConfig() (*Config, error) {
+	var cGlobal *C.snd_config_t
+	err := C.snd_config_update_ref(&cGlobal)
+	if err < 0 {
+		return nil, newDeviceError("update global config", "", err)
+	}
+	defer C.snd_config_unref(cGlobal)
+
+	config := &Config{top: true}
+	err = C.snd_config_copy(&config.cConfig, cGlobal)
+	if err < 0 {
+		return nil, newDeviceError("copy global config", "", err)
+	}
+
+	return config, nil
+}
+
+// Close deletes the tree. It does nothing for nodes of a tree.
+func (config *Config) Close() {
+	if config.top && config.cConfig != nil {
+		C.snd_config_delete(config.cConfig)
+		config.cConfig = nil
+	}
+}
+
+// newError returns Error for the failed operation on the node.
+func (config *Config) newError(op string, code C.int) error {
+	return newDeviceError(op, config.ID(), code)
+}
+
+// Load parses configuration text into the compound node, merging with the
+// present nodes.
+func (config *Config) Load(text string) error {
+	cText := C.CString(text)
+	defer C.free(unsafe.Pointer(cText))
+
+	var in *C.snd_input_t
+	err := C.snd_input_buffer_open(&in, cText, C.ssize_t(len(text)))
+	if err < 0 {
+		return config.newError("open input buffer", err)
+	}
+	defer C.snd_input_close(in)
+
+	err = C.snd_config_load(config.cConfig, in)
+	if err < 0 {
+		return config.newError("load config", err)
+	}
+
+	return nil
+}
+
+// Save returns the node in asoundrc syntax.
+func (config *Config) Save() (string, error) {
+	var out *C.snd_output_t
+	err := C.snd_output_buffer_open(&out)
+	if err < 0 {
+		return "", config.newError("open output buffer", err)
+	}
+	defer C.snd_output_close(out)
+
+	err = C.snd_config_save(config.cConfig, out)
+	if err < 0 {
+		return "", config.newError("save config", err)
+	}
+
+	var buf *C.char
+	size := C.snd_output_buffer_string(out, &buf)
+
+	return C.GoStringN(buf, C.int(size)), nil
+}
+
+// Search returns the node of the dot separated key, e.g. "pcm.test.type".
+func (config *Config) Search(key string) (*Config, error) {
+	cKey := C.CString(key)
+	defer C.free(unsafe.Pointer(cKey))
+
+	var cResult *C.snd_config_t
+	err := C.snd_config_search(config.cConfig, cKey, &cResult)
+	if err < 0 {
+		return nil, config.newError("search "+key, err)
+	}
+
+	return &Config{cConfig: cResult}, nil
+}
+
+// ID returns the node identifier, empty for the top node.
+func (config *Config) ID() string {
+	var cID *C.char
+	if C.snd_config_get_id(config.cConfig, &cID) < 0 {
+		return ""
+	}
+
+	return C.GoString(cID)
+}
+
+// Type returns the node type.
+func (config *Config) Type() ConfigType {
+	return ConfigType(C.snd_config_get_type(config.cConfig))
+}
+
+// Children returns the children of the compound node.
+func (config *Config) Children() []*Config {
+	if config.Type() != ConfigTypeCompound {
+		return nil
+	}
+
+	var children []*Config
+	end := C.snd_config_iterator_end(config.cConfig)
+	for it := C.snd_config_iterator_first(config.cConfig); it != end; it = C.snd_config_iterator_next(it) {
+		children = append(children, &Config{cConfig: C.snd_config_iterator_entry(it)})
+	}
+
+	return children
+}
+
+// Value returns the value of the leaf node as text.
+func (config *Config) Value() (string, error) {
+	var cValue *C.char
+	err := C.snd_config_get_ascii(config.cConfig, &cValue)
+	if err < 0 {
+		return "", config.newError("get value", err)
+	}
+	defer C.free(unsafe.Pointer(cValue))
+
+	return C.GoString(cValue), nil
+}
+
+// Integer returns the value of the integer node.
+func (config *Config) Integer() (int64, error) {
+	if config.Type() == ConfigTypeInteger64 {
+		var value C.longlong
+		err := C.snd_config_get_integer64(config.cConfig, &value)
+		if err < 0 {
+			return 0, config.newError("get integer", err)
+		}
+		return int64(value), nil
+	}
+
+	var value C.long
+	err := C.snd_config_get_integer(config.cConfig, &value)
+	if err < 0 {
+		return 0, config.newError("get integer", err)
+	}
+
+	return int64(value), nil
+}
+
+// Real returns the value of the real node.
+func (config *Config) Real() (float64, error) {
+	var value C.double
+	err := C.snd_config_get_real(config.cConfig, &value)
+	if err < 0 {
+		return 0, config.newError("get real", err)
+	}
+
+	return float64(value), nil
+}
+
+// StringValue returns the value of the string node.
+func (config *Config) StringValue() (string, error) {
+	var value *C.char
+	err := C.snd_config_get_string(config.cConfig, &value)
+	if err < 0 {
+		return "", config.newError("get string", err)
+	}
+
+	return C.GoString(value), nil
+}
+
+// SetValue sets the value of the leaf node from text, parsed according to
+// the node type.
+func (config *Config) SetValue(value string) error {
+	cValue := C.CString(value)
+	defer C.free(unsafe.Pointer(cValue))
+
+	err := C.snd_config_set_ascii(config.cConfig, cValue)
+	if err < 0 {
+		return config.newError("set value", err)
+	}
+
+	return nil
+}
+
+// Set sets the value of the present leaf node of the key. Use Load to add
+// new nodes.
+func (config *Config) Set(key, value string) error {
+	node, err := config.Search(key)
+	if err != nil {
+		return err
+	}
+
+	return node.SetValue(value)
+}
+
+// Remove deletes the node of the key with its children.
+func (config *Config) Remove(key string) error {
+	node, err := config.Search(key)
+	if err != nil {
+		return err
+	}
+
+	cErr := C.snd_config_delete(node.cConfig)
+	if cErr < 0 {
+		return config.newError("delete "+key, cErr)
+	}
+
+	return nil
+}
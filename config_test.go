@@ -0,0 +1,94 @@
+package alsa
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfig(t *testing.T) {
+	config, err := ParseConfig(`pcm.test { type null rate 48000 }`)
+	if err != nil {
+		t.Fatalf("ParseConfig failed. %s", err)
+	}
+	defer config.Close()
+
+	node, err := config.Search("pcm.test.type")
+	if err != nil {
+		t.Fatalf("Search failed. %s", err)
+	}
+	if value, err := node.StringValue(); err != nil || value != "null" {
+		t.Errorf("Type %q, error %v", value, err)
+	}
+
+	if err = config.Set("pcm.test.rate", "44100"); err != nil {
+		t.Fatalf("Set failed. %s", err)
+	}
+	node, err = config.Search("pcm.test.rate")
+	if err != nil {
+		t.Fatalf("Search failed. %s", err)
+	}
+	if rate, err := node.Integer(); err != nil || rate != 44100 {
+		t.Errorf("Rate %d, error %v", rate, err)
+	}
+
+	test, err := config.Search("pcm.test")
+	if err != nil {
+		t.Fatalf("Search failed. %s", err)
+	}
+	if test.Type() != ConfigTypeCompound || len(test.Children()) != 2 {
+		t.Errorf("Node %s with %d children", test.Type(), len(test.Children()))
+	}
+
+	if err = config.Remove("pcm.test.rate"); err != nil {
+		t.Fatalf("Remove failed. %s", err)
+	}
+	saved, err := config.Save()
+	if err != nil {
+		t.Fatalf("Save failed. %s", err)
+	}
+	if strings.Contains(saved, "rate") || !strings.Contains(saved, "null") {
+		t.Errorf("Saved config %q", saved)
+	}
+}
+
+func TestOpenWithConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.raw")
+	configText := `pcm.out {
+		type file
+		slave.pcm null
+		file "` + path + `"
+		format raw
+	}`
+
+	handle := New()
+	err := handle.OpenWithConfig("out", configText, StreamTypePlayback, ModeBlock)
+	if err != nil {
+		t.Fatalf("OpenWithConfig failed. %s", err)
+	}
+
+	handle.SampleFormat = SampleFormatS16LE
+	handle.SampleRate = 44100
+	handle.Channels = 2
+	if err = handle.ApplyHwParams(); err != nil {
+		t.Fatalf("ApplyHwParams failed. %s", err)
+	}
+
+	buf := make([]byte, 4096)
+	if _, err = handle.Write(buf); err != nil {
+		t.Fatalf("Write failed. %s", err)
+	}
+	if err = handle.Drain(); err != nil {
+		t.Fatalf("Drain failed. %s", err)
+	}
+	handle.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Output file missing. %s", err)
+	}
+	if info.Size() != int64(len(buf)) {
+		t.Errorf("Output file has %d bytes, expected %d", info.Size(), len(buf))
+	}
+}
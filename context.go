@@ -0,0 +1,180 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+import "C"
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// WriteContext writes given PCM data like Write, but returns promptly with
+// ctx.Err() when the context is done. Frames already written stay queued
+// and the stream keeps running.
+// Returns wrote value is total bytes was written.
+func (handle *Handle) WriteContext(ctx context.Context, buf []byte) (wrote int, err error) {
+	return handle.transferContext(ctx, buf, handle.WriteFrames)
+}
+
+// ReadContext fills buf with whole frames of PCM data like Read, but
+// returns promptly with ctx.Err() when the context is done. Frames read so
+// far are kept in buf.
+// Return read value in number of bytes read.
+func (handle *Handle) ReadContext(ctx context.Context, buf []byte) (n int, err error) {
+	return handle.transferContext(ctx, buf, handle.ReadFrames)
+}
+
+// WaitContext waits till the stream is ready for I/O or the context is done.
+// Concurrent calls wait independently; cancelling one context does not
+// wake the others.
+func (handle *Handle) WaitContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	poller, err := handle.contextPoller()
+	if err != nil {
+		return err
+	}
+	defer handle.releasePoller(poller)
+
+	stop := context.AfterFunc(ctx, poller.Wake)
+	defer stop()
+
+	for {
+		ready, err := poller.Wait([]*Handle{handle}, -1)
+		if err == ErrWoken {
+			// Wakeup may be left from an earlier call.
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if len(ready) > 0 {
+			return nil
+		}
+	}
+}
+
+// contextPoller returns an idle poller of the context aware methods, or a
+// new one when all are in use. It must be returned with releasePoller.
+func (handle *Handle) contextPoller() (*Poller, error) {
+	handle.pollerLock.Lock()
+	defer handle.pollerLock.Unlock()
+
+	if n := len(handle.pollers); n > 0 {
+		poller := handle.pollers[n-1]
+		handle.pollers = handle.pollers[:n-1]
+		return poller, nil
+	}
+
+	return NewPoller()
+}
+
+// releasePoller returns the poller taken by contextPoller for reuse.
+func (handle *Handle) releasePoller(poller *Poller) {
+	handle.pollerLock.Lock()
+	defer handle.pollerLock.Unlock()
+
+	handle.pollers = append(handle.pollers, poller)
+}
+
+// DrainContext waits for all pending frames to be played like Drain. When
+// the context is done first, pending frames are dropped and the stream is
+// prepared again before ctx.Err() is returned.
+func (handle *Handle) DrainContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	restore, err := handle.enterNonblock()
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	// Nonblocking drain returns -EAGAIN and leaves the stream draining.
+	cErr := C.snd_pcm_drain(handle.cHandle)
+	if cErr < 0 && cErr != -C.EAGAIN {
+		return handle.newError("drain", cErr)
+	}
+
+	interval := 10 * time.Millisecond
+	if handle.SampleRate > 0 && handle.PeriodSize > 0 {
+		interval = time.Duration(handle.PeriodSize) * time.Second / time.Duration(handle.SampleRate)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for handle.State() == PCMStateDraining {
+		select {
+		case <-ctx.Done():
+			if err := handle.Drop(); err != nil {
+				return err
+			}
+			if err := handle.Prepare(); err != nil {
+				return err
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
+
+// transferContext runs io in nonblocking mode over whole frames of buf,
+// waiting for the stream readiness in between.
+func (handle *Handle) transferContext(ctx context.Context, buf []byte, io func([]byte) (int, error)) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	frameSize := handle.FrameSize()
+	if frameSize == 0 {
+		return 0, ErrNotConfigured
+	}
+
+	restore, err := handle.enterNonblock()
+	if err != nil {
+		return 0, err
+	}
+	defer restore()
+
+	done := 0
+	for len(buf)-done >= frameSize {
+		frames, err := io(buf[done:])
+		done += frames * frameSize
+
+		if errors.Is(err, ErrWouldBlock) {
+			err = handle.WaitContext(ctx)
+		}
+		if err != nil {
+			return done, err
+		}
+	}
+
+	return done, nil
+}
+
+// enterNonblock switches blocking stream to nonblocking mode. Returned
+// function restores the open mode.
+func (handle *Handle) enterNonblock() (restore func(), err error) {
+	if handle.mode&ModeNonblock != 0 {
+		return func() {}, nil
+	}
+
+	cErr := C.snd_pcm_nonblock(handle.cHandle, 1)
+	if cErr < 0 {
+		return nil, handle.newError("set nonblocking mode", cErr)
+	}
+
+	return func() {
+		C.snd_pcm_nonblock(handle.cHandle, 0)
+	}, nil
+}
@@ -0,0 +1,91 @@
+package alsa
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWriteContext(t *testing.T) {
+	handle := New()
+	err := handle.Open("default", StreamTypePlayback, ModeBlock)
+	if err != nil {
+		t.Fatalf("Open failed. %s", err)
+	}
+	defer handle.Close()
+
+	handle.SampleFormat = SampleFormatS16LE
+	handle.SampleRate = 44100
+	handle.Channels = 2
+	err = handle.ApplyHwParams()
+	if err != nil {
+		t.Fatalf("ApplyHwParams failed. %s", err)
+	}
+
+	// Ten seconds of audio can not be written in 100 milliseconds.
+	buf := make([]byte, 10*handle.SampleRate*handle.FrameSize())
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	wrote, err := handle.WriteContext(ctx, buf)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("WriteContext returned %v, expected deadline error", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("WriteContext returned after %v", elapsed)
+	}
+	if wrote == 0 || wrote%handle.FrameSize() != 0 {
+		t.Errorf("Unexpected number of bytes written %d", wrote)
+	}
+
+	cancelled, cancelDrain := context.WithCancel(context.Background())
+	cancelDrain()
+	if err = handle.DrainContext(cancelled); err != context.Canceled {
+		t.Errorf("DrainContext returned %v, expected cancel error", err)
+	}
+}
+
+func TestContextPollerPerWait(t *testing.T) {
+	var handle Handle
+
+	pollers := make([]*Poller, 8)
+	var wg sync.WaitGroup
+	for i := range pollers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			poller, err := handle.contextPoller()
+			if err != nil {
+				t.Errorf("contextPoller failed. %s", err)
+			}
+			pollers[i] = poller
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[*Poller]bool)
+	for _, poller := range pollers {
+		if seen[poller] {
+			t.Fatalf("Concurrent waits share a poller")
+		}
+		seen[poller] = true
+	}
+
+	for _, poller := range pollers {
+		handle.releasePoller(poller)
+	}
+	poller, err := handle.contextPoller()
+	if err != nil {
+		t.Fatalf("contextPoller failed. %s", err)
+	}
+	if !seen[poller] {
+		t.Errorf("Released poller not reused")
+	}
+	handle.releasePoller(poller)
+
+	for _, poller := range handle.pollers {
+		poller.Close()
+	}
+}
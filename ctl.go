@@ -0,0 +1,515 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Control element interface.
+type CtlElemIface C.snd_ctl_elem_iface_t
+
+// Control element interface constants.
+const (
+	// Global control
+	CtlElemIfaceCard = C.SND_CTL_ELEM_IFACE_CARD
+	// Hardware dependent device
+	CtlElemIfaceHwdep = C.SND_CTL_ELEM_IFACE_HWDEP
+	// Mixer
+	CtlElemIfaceMixer = C.SND_CTL_ELEM_IFACE_MIXER
+	// PCM
+	CtlElemIfacePCM = C.SND_CTL_ELEM_IFACE_PCM
+	// RawMidi
+	CtlElemIfaceRawMidi = C.SND_CTL_ELEM_IFACE_RAWMIDI
+	// Timer
+	CtlElemIfaceTimer = C.SND_CTL_ELEM_IFACE_TIMER
+	// Sequencer
+	CtlElemIfaceSequencer = C.SND_CTL_ELEM_IFACE_SEQUENCER
+)
+
+// String returns ALSA name of the interface, e.g. "MIXER".
+func (iface CtlElemIface) String() string {
+	return C.GoString(C.snd_ctl_elem_iface_name(C.snd_ctl_elem_iface_t(iface)))
+}
+
+// Control element value type.
+type CtlElemType C.snd_ctl_elem_type_t
+
+// Control element type constants.
+const (
+	// Invalid type
+	CtlElemTypeNone = C.SND_CTL_ELEM_TYPE_NONE
+	// Boolean switches
+	CtlElemTypeBoolean = C.SND_CTL_ELEM_TYPE_BOOLEAN
+	// Integers
+	CtlElemTypeInteger = C.SND_CTL_ELEM_TYPE_INTEGER
+	// Indexes of enumerated items
+	CtlElemTypeEnumerated = C.SND_CTL_ELEM_TYPE_ENUMERATED
+	// Bytes
+	CtlElemTypeBytes = C.SND_CTL_ELEM_TYPE_BYTES
+	// IEC958 (S/PDIF) channel status and subcode
+	CtlElemTypeIEC958 = C.SND_CTL_ELEM_TYPE_IEC958
+	// 64 bit integers
+	CtlElemTypeInteger64 = C.SND_CTL_ELEM_TYPE_INTEGER64
+)
+
+// String returns ALSA name of the type, e.g. "BOOLEAN".
+func (elemType CtlElemType) String() string {
+	return C.GoString(C.snd_ctl_elem_type_name(C.snd_ctl_elem_type_t(elemType)))
+}
+
+// Ctl is a control interface of a sound card.
+type Ctl struct {
+	cCtl *C.snd_ctl_t
+	name string
+}
+
+// CardInfo describes a sound card.
+type CardInfo struct {
+	// Card index.
+	Card int
+	// Card identifier, e.g. "PCH".
+	ID string
+	// Driver name.
+	Driver string
+	// Short card name.
+	Name string
+	// Long card name.
+	LongName string
+	// Mixer chip name.
+	MixerName string
+	// Card components separated by spaces.
+	Components string
+}
+
+// CtlElemID identifies a control element. Element is found by NumID when
+// it is not zero, by the rest of fields otherwise.
+type CtlElemID struct {
+	// Numeric identifier assigned by the card.
+	NumID int
+	// Interface the element belongs to.
+	Interface CtlElemIface
+	// Device index the element is bound to.
+	Device int
+	// Subdevice index the element is bound to.
+	Subdevice int
+	// Element name, e.g. "Headphone Jack".
+	Name string
+	// Index distinguishing elements of one name.
+	Index int
+}
+
+// CtlElemInfo describes a control element.
+type CtlElemInfo struct {
+	// Element identifier.
+	ID CtlElemID
+	// Type of the element values.
+	Type CtlElemType
+	// Number of values of the element, e.g. one per channel.
+	Count int
+	// Values can be read.
+	Readable bool
+	// Values can be written.
+	Writable bool
+	// Values may change without notification.
+	Volatile bool
+	// Element is inactive.
+	Inactive bool
+	// Element is locked by another client.
+	Locked bool
+	// TLV data, e.g. the dB scale, can be read.
+	TLVReadable bool
+	// Value range of INTEGER and INTEGER64 elements.
+	Min, Max, Step int64
+	// Item names of ENUMERATED elements.
+	Items []string
+}
+
+// IEC958 is the IEC958 (S/PDIF) channel status, subcode and subframe.
+type IEC958 struct {
+	// AES/IEC958 channel status bits.
+	Status [24]byte
+	// AES/IEC958 subcode bits.
+	Subcode [147]byte
+	// Nibble of each subframe, e.g. validity and user bits.
+	DigSubframe [4]byte
+}
+
+// CtlElemValue holds values of a control element. Only the slice matching
+// Type is used; INTEGER and INTEGER64 values share Integers.
+type CtlElemValue struct {
+	// Type of the element values.
+	Type CtlElemType
+	// Values of BOOLEAN elements.
+	Booleans []bool
+	// Values of INTEGER and INTEGER64 elements.
+	Integers []int64
+	// Item indexes of ENUMERATED elements.
+	Enumerated []int
+	// Values of BYTES elements.
+	Bytes []byte
+	// Value of IEC958 elements.
+	IEC958 *IEC958
+}
+
+// String returns the identifier in amixer format, e.g.
+// "numid=3,iface=MIXER,name='Master Playback Volume'".
+func (id CtlElemID) String() string {
+	s := fmt.Sprintf("numid=%d,iface=%v,name='%s'", id.NumID, id.Interface, id.Name)
+	if id.Index != 0 {
+		s += fmt.Sprintf(",index=%d", id.Index)
+	}
+	if id.Device != 0 {
+		s += fmt.Sprintf(",device=%d", id.Device)
+	}
+	if id.Subdevice != 0 {
+		s += fmt.Sprintf(",subdevice=%d", id.Subdevice)
+	}
+
+	return s
+}
+
+// OpenCtl opens control interface of the card, e.g. "default" or "hw:0".
+func OpenCtl(name string) (*Ctl, error) {
+	ctl := &Ctl{name: name}
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	err := C.snd_ctl_open(&ctl.cCtl, cName, 0)
+	if err < 0 {
+		return nil, ctl.newError("open control", err)
+	}
+
+	return ctl, nil
+}
+
+// Close closes the control interface.
+func (ctl *Ctl) Close() {
+	if ctl.cCtl != nil {
+		C.snd_ctl_close(ctl.cCtl)
+		ctl.cCtl = nil
+	}
+}
+
+// newError returns Error for the failed operation on the control.
+func (ctl *Ctl) newError(op string, code C.int) error {
+	return newDeviceError(op, ctl.name, code)
+}
+
+// CardInfo returns information about the card.
+func (ctl *Ctl) CardInfo() (*CardInfo, error) {
+	var cInfo *C.snd_ctl_card_info_t
+	err := C.snd_ctl_card_info_malloc(&cInfo)
+	if err < 0 {
+		return nil, ctl.newError("allocate card info", err)
+	}
+	defer C.snd_ctl_card_info_free(cInfo)
+
+	err = C.snd_ctl_card_info(ctl.cCtl, cInfo)
+	if err < 0 {
+		return nil, ctl.newError("get card info", err)
+	}
+
+	return &CardInfo{
+		Card:       int(C.snd_ctl_card_info_get_card(cInfo)),
+		ID:         C.GoString(C.snd_ctl_card_info_get_id(cInfo)),
+		Driver:     C.GoString(C.snd_ctl_card_info_get_driver(cInfo)),
+		Name:       C.GoString(C.snd_ctl_card_info_get_name(cInfo)),
+		LongName:   C.GoString(C.snd_ctl_card_info_get_longname(cInfo)),
+		MixerName:  C.GoString(C.snd_ctl_card_info_get_mixername(cInfo)),
+		Components: C.GoString(C.snd_ctl_card_info_get_components(cInfo)),
+	}, nil
+}
+
+// Elements returns identifiers of all control elements of the card.
+func (ctl *Ctl) Elements() ([]CtlElemID, error) {
+	var cList *C.snd_ctl_elem_list_t
+	err := C.snd_ctl_elem_list_malloc(&cList)
+	if err < 0 {
+		return nil, ctl.newError("allocate element list", err)
+	}
+	defer C.snd_ctl_elem_list_free(cList)
+
+	// First call gets the count only.
+	err = C.snd_ctl_elem_list(ctl.cCtl, cList)
+	if err < 0 {
+		return nil, ctl.newError("list elements", err)
+	}
+
+	err = C.snd_ctl_elem_list_alloc_space(cList, C.snd_ctl_elem_list_get_count(cList))
+	if err < 0 {
+		return nil, ctl.newError("allocate element list", err)
+	}
+	defer C.snd_ctl_elem_list_free_space(cList)
+
+	err = C.snd_ctl_elem_list(ctl.cCtl, cList)
+	if err < 0 {
+		return nil, ctl.newError("list elements", err)
+	}
+
+	var cID *C.snd_ctl_elem_id_t
+	err = C.snd_ctl_elem_id_malloc(&cID)
+	if err < 0 {
+		return nil, ctl.newError("allocate element id", err)
+	}
+	defer C.snd_ctl_elem_id_free(cID)
+
+	ids := make([]CtlElemID, C.snd_ctl_elem_list_get_used(cList))
+	for i := range ids {
+		C.snd_ctl_elem_list_get_id(cList, C.uint(i), cID)
+		ids[i] = goCtlElemID(cID)
+	}
+
+	return ids, nil
+}
+
+// ElemInfo returns description of the element.
+func (ctl *Ctl) ElemInfo(id CtlElemID) (*CtlElemInfo, error) {
+	cInfo, err := ctl.elemInfo(id)
+	if err != nil {
+		return nil, err
+	}
+	defer C.snd_ctl_elem_info_free(cInfo)
+
+	info := &CtlElemInfo{
+		Type:        CtlElemType(C.snd_ctl_elem_info_get_type(cInfo)),
+		Count:       int(C.snd_ctl_elem_info_get_count(cInfo)),
+		Readable:    C.snd_ctl_elem_info_is_readable(cInfo) != 0,
+		Writable:    C.snd_ctl_elem_info_is_writable(cInfo) != 0,
+		Volatile:    C.snd_ctl_elem_info_is_volatile(cInfo) != 0,
+		Inactive:    C.snd_ctl_elem_info_is_inactive(cInfo) != 0,
+		Locked:      C.snd_ctl_elem_info_is_locked(cInfo) != 0,
+		TLVReadable: C.snd_ctl_elem_info_is_tlv_readable(cInfo) != 0,
+	}
+
+	var cID *C.snd_ctl_elem_id_t
+	if cErr := C.snd_ctl_elem_id_malloc(&cID); cErr < 0 {
+		return nil, ctl.newError("allocate element id", cErr)
+	}
+	defer C.snd_ctl_elem_id_free(cID)
+
+	C.snd_ctl_elem_info_get_id(cInfo, cID)
+	info.ID = goCtlElemID(cID)
+
+	switch info.Type {
+	case CtlElemTypeInteger:
+		info.Min = int64(C.snd_ctl_elem_info_get_min(cInfo))
+		info.Max = int64(C.snd_ctl_elem_info_get_max(cInfo))
+		info.Step = int64(C.snd_ctl_elem_info_get_step(cInfo))
+	case CtlElemTypeInteger64:
+		info.Min = int64(C.snd_ctl_elem_info_get_min64(cInfo))
+		info.Max = int64(C.snd_ctl_elem_info_get_max64(cInfo))
+		info.Step = int64(C.snd_ctl_elem_info_get_step64(cInfo))
+	case CtlElemTypeEnumerated:
+		info.Items = make([]string, C.snd_ctl_elem_info_get_items(cInfo))
+		for i := range info.Items {
+			// Item name is fetched by another info call.
+			C.snd_ctl_elem_info_set_item(cInfo, C.uint(i))
+			if cErr := C.snd_ctl_elem_info(ctl.cCtl, cInfo); cErr < 0 {
+				return nil, ctl.newError("get element item", cErr)
+			}
+			info.Items[i] = C.GoString(C.snd_ctl_elem_info_get_item_name(cInfo))
+		}
+	}
+
+	return info, nil
+}
+
+// ReadElem returns current values of the element.
+func (ctl *Ctl) ReadElem(id CtlElemID) (*CtlElemValue, error) {
+	info, err := ctl.ElemInfo(id)
+	if err != nil {
+		return nil, err
+	}
+
+	cValue, err := ctl.elemValue(info.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer C.snd_ctl_elem_value_free(cValue)
+
+	cErr := C.snd_ctl_elem_read(ctl.cCtl, cValue)
+	if cErr < 0 {
+		return nil, ctl.newError("read element", cErr)
+	}
+
+	value := &CtlElemValue{Type: info.Type}
+	switch info.Type {
+	case CtlElemTypeBoolean:
+		value.Booleans = make([]bool, info.Count)
+		for i := range value.Booleans {
+			value.Booleans[i] = C.snd_ctl_elem_value_get_boolean(cValue, C.uint(i)) != 0
+		}
+	case CtlElemTypeInteger:
+		value.Integers = make([]int64, info.Count)
+		for i := range value.Integers {
+			value.Integers[i] = int64(C.snd_ctl_elem_value_get_integer(cValue, C.uint(i)))
+		}
+	case CtlElemTypeInteger64:
+		value.Integers = make([]int64, info.Count)
+		for i := range value.Integers {
+			value.Integers[i] = int64(C.snd_ctl_elem_value_get_integer64(cValue, C.uint(i)))
+		}
+	case CtlElemTypeEnumerated:
+		value.Enumerated = make([]int, info.Count)
+		for i := range value.Enumerated {
+			value.Enumerated[i] = int(C.snd_ctl_elem_value_get_enumerated(cValue, C.uint(i)))
+		}
+	case CtlElemTypeBytes:
+		value.Bytes = make([]byte, info.Count)
+		for i := range value.Bytes {
+			value.Bytes[i] = byte(C.snd_ctl_elem_value_get_byte(cValue, C.uint(i)))
+		}
+	case CtlElemTypeIEC958:
+		var cIEC958 C.snd_aes_iec958_t
+		C.snd_ctl_elem_value_get_iec958(cValue, &cIEC958)
+		value.IEC958 = new(IEC958)
+		copy(value.IEC958.Status[:], C.GoBytes(unsafe.Pointer(&cIEC958.status[0]), C.int(len(cIEC958.status))))
+		copy(value.IEC958.Subcode[:], C.GoBytes(unsafe.Pointer(&cIEC958.subcode[0]), C.int(len(cIEC958.subcode))))
+		copy(value.IEC958.DigSubframe[:], C.GoBytes(unsafe.Pointer(&cIEC958.dig_subframe[0]), C.int(len(cIEC958.dig_subframe))))
+	}
+
+	return value, nil
+}
+
+// WriteElem writes values of the element. All values of the element are
+// written, so value is best obtained with ReadElem and modified.
+func (ctl *Ctl) WriteElem(id CtlElemID, value *CtlElemValue) error {
+	cValue, err := ctl.elemValue(id)
+	if err != nil {
+		return err
+	}
+	defer C.snd_ctl_elem_value_free(cValue)
+
+	switch value.Type {
+	case CtlElemTypeBoolean:
+		for i, b := range value.Booleans {
+			C.snd_ctl_elem_value_set_boolean(cValue, C.uint(i), C.long(cBool(b)))
+		}
+	case CtlElemTypeInteger:
+		for i, v := range value.Integers {
+			C.snd_ctl_elem_value_set_integer(cValue, C.uint(i), C.long(v))
+		}
+	case CtlElemTypeInteger64:
+		for i, v := range value.Integers {
+			C.snd_ctl_elem_value_set_integer64(cValue, C.uint(i), C.longlong(v))
+		}
+	case CtlElemTypeEnumerated:
+		for i, v := range value.Enumerated {
+			C.snd_ctl_elem_value_set_enumerated(cValue, C.uint(i), C.uint(v))
+		}
+	case CtlElemTypeBytes:
+		for i, v := range value.Bytes {
+			C.snd_ctl_elem_value_set_byte(cValue, C.uint(i), C.uchar(v))
+		}
+	case CtlElemTypeIEC958:
+		if value.IEC958 == nil {
+			return ctl.newError("write element", -C.EINVAL)
+		}
+		var cIEC958 C.snd_aes_iec958_t
+		for i, v := range value.IEC958.Status {
+			cIEC958.status[i] = C.uchar(v)
+		}
+		for i, v := range value.IEC958.Subcode {
+			cIEC958.subcode[i] = C.uchar(v)
+		}
+		for i, v := range value.IEC958.DigSubframe {
+			cIEC958.dig_subframe[i] = C.uchar(v)
+		}
+		C.snd_ctl_elem_value_set_iec958(cValue, &cIEC958)
+	default:
+		return ctl.newError("write element", -C.EINVAL)
+	}
+
+	cErr := C.snd_ctl_elem_write(ctl.cCtl, cValue)
+	if cErr < 0 {
+		return ctl.newError("write element", cErr)
+	}
+
+	return nil
+}
+
+// elemInfo returns filled element info. It must be released with
+// snd_ctl_elem_info_free.
+func (ctl *Ctl) elemInfo(id CtlElemID) (*C.snd_ctl_elem_info_t, error) {
+	cID, err := ctl.cElemID(id)
+	if err != nil {
+		return nil, err
+	}
+	defer C.snd_ctl_elem_id_free(cID)
+
+	var cInfo *C.snd_ctl_elem_info_t
+	cErr := C.snd_ctl_elem_info_malloc(&cInfo)
+	if cErr < 0 {
+		return nil, ctl.newError("allocate element info", cErr)
+	}
+
+	C.snd_ctl_elem_info_set_id(cInfo, cID)
+	cErr = C.snd_ctl_elem_info(ctl.cCtl, cInfo)
+	if cErr < 0 {
+		C.snd_ctl_elem_info_free(cInfo)
+		return nil, ctl.newError("get element info", cErr)
+	}
+
+	return cInfo, nil
+}
+
+// elemValue returns element value container for the element. It must be
+// released with snd_ctl_elem_value_free.
+func (ctl *Ctl) elemValue(id CtlElemID) (*C.snd_ctl_elem_value_t, error) {
+	cID, err := ctl.cElemID(id)
+	if err != nil {
+		return nil, err
+	}
+	defer C.snd_ctl_elem_id_free(cID)
+
+	var cValue *C.snd_ctl_elem_value_t
+	cErr := C.snd_ctl_elem_value_malloc(&cValue)
+	if cErr < 0 {
+		return nil, ctl.newError("allocate element value", cErr)
+	}
+	C.snd_ctl_elem_value_set_id(cValue, cID)
+
+	return cValue, nil
+}
+
+// cElemID returns ALSA element identifier. It must be released with
+// snd_ctl_elem_id_free.
+func (ctl *Ctl) cElemID(id CtlElemID) (*C.snd_ctl_elem_id_t, error) {
+	var cID *C.snd_ctl_elem_id_t
+	err := C.snd_ctl_elem_id_malloc(&cID)
+	if err < 0 {
+		return nil, ctl.newError("allocate element id", err)
+	}
+
+	if id.NumID != 0 {
+		C.snd_ctl_elem_id_set_numid(cID, C.uint(id.NumID))
+		return cID, nil
+	}
+
+	cName := C.CString(id.Name)
+	defer C.free(unsafe.Pointer(cName))
+
+	C.snd_ctl_elem_id_set_interface(cID, C.snd_ctl_elem_iface_t(id.Interface))
+	C.snd_ctl_elem_id_set_device(cID, C.uint(id.Device))
+	C.snd_ctl_elem_id_set_subdevice(cID, C.uint(id.Subdevice))
+	C.snd_ctl_elem_id_set_name(cID, cName)
+	C.snd_ctl_elem_id_set_index(cID, C.uint(id.Index))
+
+	return cID, nil
+}
+
+// goCtlElemID returns element identifier from the ALSA one.
+func goCtlElemID(cID *C.snd_ctl_elem_id_t) CtlElemID {
+	return CtlElemID{
+		NumID:     int(C.snd_ctl_elem_id_get_numid(cID)),
+		Interface: CtlElemIface(C.snd_ctl_elem_id_get_interface(cID)),
+		Device:    int(C.snd_ctl_elem_id_get_device(cID)),
+		Subdevice: int(C.snd_ctl_elem_id_get_subdevice(cID)),
+		Name:      C.GoString(C.snd_ctl_elem_id_get_name(cID)),
+		Index:     int(C.snd_ctl_elem_id_get_index(cID)),
+	}
+}
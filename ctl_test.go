@@ -0,0 +1,58 @@
+package alsa
+
+import (
+	"testing"
+)
+
+func TestCtl(t *testing.T) {
+	ctl, err := OpenCtl("default")
+	if err != nil {
+		t.Fatalf("OpenCtl failed. %s", err)
+	}
+	defer ctl.Close()
+
+	card, err := ctl.CardInfo()
+	if err != nil {
+		t.Fatalf("CardInfo failed. %s", err)
+	}
+	if card.ID == "" || card.Driver == "" {
+		t.Errorf("Card info without id or driver %+v", card)
+	}
+
+	ids, err := ctl.Elements()
+	if err != nil {
+		t.Fatalf("Elements failed. %s", err)
+	}
+
+	for _, id := range ids {
+		info, err := ctl.ElemInfo(id)
+		if err != nil {
+			t.Errorf("ElemInfo of %v failed. %s", id, err)
+			continue
+		}
+		if info.ID.NumID != id.NumID {
+			t.Errorf("Info of %v is for %v", id, info.ID)
+		}
+		if !info.Readable || info.Type == CtlElemTypeNone {
+			continue
+		}
+
+		value, err := ctl.ReadElem(id)
+		if err != nil {
+			t.Errorf("ReadElem of %v failed. %s", id, err)
+			continue
+		}
+		if value.Type != info.Type {
+			t.Errorf("Value of %v has type %v, expected %v", id, value.Type, info.Type)
+		}
+		if info.Type == CtlElemTypeInteger && len(value.Integers) != info.Count {
+			t.Errorf("Read %d values of %v, expected %d", len(value.Integers), id, info.Count)
+		}
+
+		if info.TLVReadable {
+			if _, err = ctl.ElemDBScale(id); err != nil {
+				t.Errorf("ElemDBScale of %v failed. %s", id, err)
+			}
+		}
+	}
+}
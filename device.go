@@ -0,0 +1,128 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Device describes a PCM device name usable with Open, including plugins
+// like dmix, plughw and sysdefault.
+type Device struct {
+	// Device name to pass to Open, e.g. "plughw:CARD=PCH,DEV=0".
+	Name string
+	// Human readable description. May span several lines.
+	Description string
+	// Stream direction of the device: "Input", "Output" or empty when the
+	// device supports both.
+	IOID string
+}
+
+// Card describes a sound card.
+type Card struct {
+	// Card index, as used in "hw:N" device names.
+	Index int
+	// Short card name.
+	Name string
+	// Long card name.
+	LongName string
+}
+
+// ListDevices returns PCM devices of all cards and configured plugins
+// which support given stream type.
+func ListDevices(streamType StreamType) ([]Device, error) {
+	cIface := C.CString("pcm")
+	defer C.free(unsafe.Pointer(cIface))
+
+	var hints *unsafe.Pointer
+	err := C.snd_device_name_hint(-1, cIface, &hints)
+	if err < 0 {
+		return nil, newError("get device name hints", "", streamType, err)
+	}
+	defer C.snd_device_name_free_hint(hints)
+
+	ioid := "Output"
+	if streamType == StreamTypeCapture {
+		ioid = "Input"
+	}
+
+	var devices []Device
+	for _, hint := range unsafe.Slice(hints, hintsCount(hints)) {
+		device := Device{
+			Name:        deviceHint(hint, "NAME"),
+			Description: deviceHint(hint, "DESC"),
+			IOID:        deviceHint(hint, "IOID"),
+		}
+		if device.Name == "" || (device.IOID != "" && device.IOID != ioid) {
+			continue
+		}
+
+		devices = append(devices, device)
+	}
+
+	return devices, nil
+}
+
+// ListCards returns sound cards present in the system.
+func ListCards() ([]Card, error) {
+	var cards []Card
+
+	index := C.int(-1)
+	for {
+		err := C.snd_card_next(&index)
+		if err < 0 {
+			return cards, newDeviceError("get next card", "", err)
+		}
+		if index < 0 {
+			break
+		}
+
+		device := fmt.Sprintf("hw:%d", index)
+		card := Card{Index: int(index)}
+
+		var cName *C.char
+		err = C.snd_card_get_name(index, &cName)
+		if err < 0 {
+			return cards, newDeviceError("get card name", device, err)
+		}
+		card.Name = C.GoString(cName)
+		C.free(unsafe.Pointer(cName))
+
+		err = C.snd_card_get_longname(index, &cName)
+		if err < 0 {
+			return cards, newDeviceError("get card long name", device, err)
+		}
+		card.LongName = C.GoString(cName)
+		C.free(unsafe.Pointer(cName))
+
+		cards = append(cards, card)
+	}
+
+	return cards, nil
+}
+
+// hintsCount returns length of the NULL terminated hints array.
+func hintsCount(hints *unsafe.Pointer) int {
+	count := 0
+	for hint := hints; *hint != nil; hint = (*unsafe.Pointer)(unsafe.Add(unsafe.Pointer(hint), unsafe.Sizeof(*hint))) {
+		count++
+	}
+
+	return count
+}
+
+// deviceHint returns hint field by its id, or empty string when absent.
+func deviceHint(hint unsafe.Pointer, id string) string {
+	cID := C.CString(id)
+	defer C.free(unsafe.Pointer(cID))
+
+	cValue := C.snd_device_name_get_hint(hint, cID)
+	if cValue == nil {
+		return ""
+	}
+	defer C.free(unsafe.Pointer(cValue))
+
+	return C.GoString(cValue)
+}
@@ -0,0 +1,44 @@
+package alsa
+
+import (
+	"testing"
+)
+
+func TestListDevices(t *testing.T) {
+	devices, err := ListDevices(StreamTypePlayback)
+	if err != nil {
+		t.Fatalf("ListDevices failed. %s", err)
+	}
+
+	found := false
+	for _, device := range devices {
+		if device.Name == "" {
+			t.Errorf("Empty device name")
+		}
+		if device.IOID == "Input" {
+			t.Errorf("Capture only device %s listed for playback", device.Name)
+		}
+		if device.Name == "default" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Default device is not listed")
+	}
+}
+
+func TestListCards(t *testing.T) {
+	cards, err := ListCards()
+	if err != nil {
+		t.Fatalf("ListCards failed. %s", err)
+	}
+
+	for i, card := range cards {
+		if card.Name == "" || card.LongName == "" {
+			t.Errorf("Card %d has no name", card.Index)
+		}
+		if i > 0 && card.Index <= cards[i-1].Index {
+			t.Errorf("Cards are not in index order")
+		}
+	}
+}
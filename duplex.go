@@ -0,0 +1,388 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+import "C"
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// DuplexProcess gets a period of captured frames in and fills out with a
+// period of frames to play. Buffers are reused between calls and must not
+// be retained.
+type DuplexProcess func(in, out []byte)
+
+// Duplex is a full-duplex engine over paired capture and playback streams
+// with matched hardware parameters.
+type Duplex struct {
+	Capture  *Handle
+	Playback *Handle
+	// Used samples format (size, endianness, signed).
+	SampleFormat SampleFormat
+	// Sample rate in Hz.
+	SampleRate int
+	// Channels in both streams.
+	Channels int
+	// Size of period in frames. Process is called once per period.
+	PeriodSize int
+	// Number of periods in the buffers.
+	Periods int
+	// Frames of silence queued to playback before start. It is the target
+	// round trip latency. Two periods if zero.
+	Latency int
+	// Streams are linked and start together.
+	linked bool
+	// Playback start threshold replaced by prime, restored on Stop.
+	startThreshold      int
+	startThresholdSaved bool
+	// Running engine, guarded by engineLock.
+	engine     *callbackEngine
+	engineLock sync.Mutex
+}
+
+// OpenDuplex opens capture and playback devices for a duplex engine.
+func OpenDuplex(captureDevice, playbackDevice string) (*Duplex, error) {
+	duplex := &Duplex{Capture: New(), Playback: New()}
+
+	err := duplex.Capture.Open(captureDevice, StreamTypeCapture, ModeBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	err = duplex.Playback.Open(playbackDevice, StreamTypePlayback, ModeBlock)
+	if err != nil {
+		duplex.Capture.Close()
+		return nil, err
+	}
+
+	return duplex, nil
+}
+
+// ApplyHwParams applies the duplex parameters to the playback stream, then
+// the values it granted to the capture stream, and links the streams when
+// the devices allow it. Granted values are written back to the duplex.
+func (duplex *Duplex) ApplyHwParams() error {
+	if duplex.linked {
+		C.snd_pcm_unlink(duplex.Capture.cHandle)
+		duplex.linked = false
+	}
+
+	playback := duplex.Playback
+	playback.SampleFormat = duplex.SampleFormat
+	playback.SampleRate = duplex.SampleRate
+	playback.Channels = duplex.Channels
+	playback.PeriodSize = duplex.PeriodSize
+	playback.Periods = duplex.Periods
+	err := playback.ApplyHwParams()
+	if err != nil {
+		return err
+	}
+
+	capture := duplex.Capture
+	capture.SampleFormat = playback.SampleFormat
+	capture.SampleRate = playback.SampleRate
+	capture.Channels = playback.Channels
+	capture.PeriodSize = playback.PeriodSize
+	capture.Periods = playback.Periods
+	capture.Buffersize = playback.Buffersize
+	err = capture.ApplyHwParams()
+	if err != nil {
+		return err
+	}
+
+	if capture.SampleFormat != playback.SampleFormat || capture.SampleRate != playback.SampleRate ||
+		capture.Channels != playback.Channels || capture.PeriodSize != playback.PeriodSize {
+		return capture.newError("match duplex parameters", -C.EINVAL)
+	}
+
+	duplex.SampleFormat = playback.SampleFormat
+	duplex.SampleRate = playback.SampleRate
+	duplex.Channels = playback.Channels
+	duplex.PeriodSize = playback.PeriodSize
+	duplex.Periods = playback.Periods
+
+	// Wake up the engine once per captured period.
+	params, err := capture.SwParams()
+	if err != nil {
+		return err
+	}
+	defer params.Free()
+
+	err = params.SetAvailMin(capture.PeriodSize)
+	if err != nil {
+		return err
+	}
+
+	err = params.Apply()
+	if err != nil {
+		return err
+	}
+
+	duplex.linked = C.snd_pcm_link(capture.cHandle, playback.cHandle) == 0
+
+	return nil
+}
+
+// Linked reports whether the streams are linked and start together.
+func (duplex *Duplex) Linked() bool {
+	return duplex.linked
+}
+
+// Start primes playback with Latency frames of silence, starts both
+// streams and calls process once per period from a dedicated goroutine
+// locked to an OS thread, till Stop or Close. Xruns are handled according
+// to the Recovery mode of each handle.
+func (duplex *Duplex) Start(process DuplexProcess) error {
+	if duplex.Playback.FrameSize() == 0 || duplex.PeriodSize == 0 {
+		return ErrNotConfigured
+	}
+
+	duplex.engineLock.Lock()
+	defer duplex.engineLock.Unlock()
+
+	if duplex.engine != nil {
+		if !duplex.engine.finished() {
+			return duplex.Playback.newError("start duplex", -C.EBUSY)
+		}
+		// Engine stopped by an error is replaced.
+		duplex.engine.poller.Close()
+		duplex.engine = nil
+	}
+
+	poller, err := NewPoller()
+	if err != nil {
+		return err
+	}
+
+	engine := &callbackEngine{
+		poller: poller,
+		done:   make(chan struct{}),
+	}
+	duplex.engine = engine
+
+	started := make(chan error)
+	go duplex.run(engine, process, started)
+
+	err = <-started
+	if err != nil {
+		<-engine.done
+		duplex.engine = nil
+		poller.Close()
+		duplex.restoreStartThreshold()
+	}
+
+	return err
+}
+
+// Stop stops the engine and drops pending frames of both streams.
+// Returns the error which stopped the engine earlier, if any.
+func (duplex *Duplex) Stop() error {
+	duplex.engineLock.Lock()
+	defer duplex.engineLock.Unlock()
+
+	engine := duplex.engine
+	if engine == nil {
+		return nil
+	}
+	duplex.engine = nil
+
+	engine.stop()
+
+	C.snd_pcm_drop(duplex.Playback.cHandle)
+	C.snd_pcm_drop(duplex.Capture.cHandle)
+
+	if err := duplex.restoreStartThreshold(); err != nil && engine.err == nil {
+		return err
+	}
+
+	return engine.err
+}
+
+// restoreStartThreshold restores the playback start threshold saved by
+// prime, so plain writes start the stream again.
+func (duplex *Duplex) restoreStartThreshold() error {
+	if !duplex.startThresholdSaved {
+		return nil
+	}
+
+	params, err := duplex.Playback.SwParams()
+	if err != nil {
+		return err
+	}
+	defer params.Free()
+
+	if err = params.SetStartThreshold(duplex.startThreshold); err != nil {
+		return err
+	}
+	if err = params.Apply(); err != nil {
+		return err
+	}
+
+	duplex.startThresholdSaved = false
+
+	return nil
+}
+
+// Close stops the engine, unlinks and closes both streams.
+func (duplex *Duplex) Close() {
+	duplex.Stop()
+
+	if duplex.linked {
+		C.snd_pcm_unlink(duplex.Capture.cHandle)
+		duplex.linked = false
+	}
+
+	duplex.Capture.Close()
+	duplex.Playback.Close()
+}
+
+// RoundTripLatency returns measured delay between capturing a frame and
+// playing it: frames captured but not yet read plus frames queued for
+// playback.
+func (duplex *Duplex) RoundTripLatency() (time.Duration, error) {
+	captureDelay, err := duplex.Capture.Delay()
+	if err != nil {
+		return 0, err
+	}
+
+	playbackDelay, err := duplex.Playback.Delay()
+	if err != nil {
+		return 0, err
+	}
+
+	frames := captureDelay + playbackDelay
+
+	return time.Duration(frames) * time.Second / time.Duration(duplex.SampleRate), nil
+}
+
+// run is the engine goroutine.
+func (duplex *Duplex) run(engine *callbackEngine, process DuplexProcess, started chan<- error) {
+	defer close(engine.done)
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	capture := duplex.Capture
+	playback := duplex.Playback
+
+	err := duplex.prime()
+	if err != nil {
+		started <- err
+		return
+	}
+	started <- nil
+
+	periodBytes := duplex.PeriodSize * playback.FrameSize()
+	in := make([]byte, periodBytes)
+	out := make([]byte, periodBytes)
+
+	for !engine.stopped.Load() {
+		_, err = engine.poller.Wait([]*Handle{capture}, -1)
+		if err == ErrWoken {
+			continue
+		}
+		if err != nil {
+			engine.err = err
+			return
+		}
+
+		// Wait for a whole period; errors are left to ReadFrames recovery.
+		avail := C.snd_pcm_avail_update(capture.cHandle)
+		if avail >= 0 && int(avail) < duplex.PeriodSize {
+			continue
+		}
+
+		_, err = capture.ReadFrames(in)
+		if err != nil {
+			engine.err = err
+			return
+		}
+
+		process(in, out)
+
+		_, err = playback.WriteFrames(out)
+		if err != nil {
+			engine.err = err
+			return
+		}
+
+		// Playback does not start on its own after xrun recovery.
+		if playback.State() == PCMStatePrepared {
+			if err = playback.Start(); err != nil {
+				engine.err = err
+				return
+			}
+		}
+	}
+}
+
+// prime restarts both streams with Latency frames of silence queued for
+// playback.
+func (duplex *Duplex) prime() error {
+	capture := duplex.Capture
+	playback := duplex.Playback
+
+	C.snd_pcm_drop(playback.cHandle)
+	C.snd_pcm_drop(capture.cHandle)
+
+	if err := playback.Prepare(); err != nil {
+		return err
+	}
+	if err := capture.Prepare(); err != nil {
+		return err
+	}
+
+	latency := duplex.Latency
+	if latency <= 0 {
+		latency = 2 * duplex.PeriodSize
+	}
+	if latency > playback.Buffersize {
+		latency = playback.Buffersize
+	}
+
+	silence := make([]byte, latency*playback.FrameSize())
+	playback.fillSilence(silence)
+
+	// Keep playback from starting on its own before capture is ready.
+	params, err := playback.SwParams()
+	if err != nil {
+		return err
+	}
+	defer params.Free()
+
+	boundary, err := params.Boundary()
+	if err != nil {
+		return err
+	}
+	// Threshold of an earlier failed run is the boundary already.
+	if !duplex.startThresholdSaved {
+		threshold, err := params.StartThreshold()
+		if err != nil {
+			return err
+		}
+		duplex.startThreshold = threshold
+	}
+	if err = params.SetStartThreshold(boundary); err != nil {
+		return err
+	}
+	if err = params.Apply(); err != nil {
+		return err
+	}
+	duplex.startThresholdSaved = true
+
+	if _, err = playback.WriteFrames(silence); err != nil {
+		return err
+	}
+
+	// Linked streams start together.
+	if err = playback.Start(); err != nil {
+		return err
+	}
+	if !duplex.linked {
+		return capture.Start()
+	}
+
+	return nil
+}
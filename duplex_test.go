@@ -0,0 +1,74 @@
+package alsa
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDuplex(t *testing.T) {
+	duplex, err := OpenDuplex("default", "default")
+	if err != nil {
+		t.Fatalf("OpenDuplex failed. %s", err)
+	}
+	defer duplex.Close()
+
+	duplex.SampleFormat = SampleFormatS16LE
+	duplex.SampleRate = 48000
+	duplex.Channels = 2
+	duplex.PeriodSize = 256
+	duplex.Periods = 4
+	err = duplex.ApplyHwParams()
+	if err != nil {
+		t.Fatalf("ApplyHwParams failed. %s", err)
+	}
+
+	periods := 0
+	err = duplex.Start(func(in, out []byte) {
+		if len(in) != len(out) || len(in) != duplex.PeriodSize*duplex.Capture.FrameSize() {
+			t.Errorf("Unexpected buffer sizes %d and %d", len(in), len(out))
+		}
+		copy(out, in)
+		periods++
+	})
+	if err != nil {
+		t.Fatalf("Start failed. %s", err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	latency, err := duplex.RoundTripLatency()
+	if err != nil {
+		t.Errorf("RoundTripLatency failed. %s", err)
+	} else if latency <= 0 {
+		t.Errorf("Invalid round trip latency %v", latency)
+	}
+
+	if err = duplex.Stop(); err != nil {
+		t.Fatalf("Stop failed. %s", err)
+	}
+	if periods == 0 {
+		t.Errorf("Process was not called")
+	}
+
+	// Stopped engine can be started again.
+	if err = duplex.Start(func(in, out []byte) {}); err != nil {
+		t.Fatalf("Restarting Start failed. %s", err)
+	}
+	if err = duplex.Stop(); err != nil {
+		t.Fatalf("Stop failed. %s", err)
+	}
+
+	// Plain writes start the playback stream again after Stop.
+	params, err := duplex.Playback.SwParams()
+	if err != nil {
+		t.Fatalf("SwParams failed. %s", err)
+	}
+	defer params.Free()
+	threshold, err := params.StartThreshold()
+	if err != nil {
+		t.Fatalf("StartThreshold failed. %s", err)
+	}
+	if boundary, _ := params.Boundary(); threshold >= boundary {
+		t.Errorf("Start threshold %d was not restored", threshold)
+	}
+}
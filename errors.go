@@ -0,0 +1,110 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+// Sentinel errors matched by Error with errors.Is.
+var (
+	// Playback buffer ran empty (-EPIPE on a playback stream).
+	ErrUnderrun = errors.New("alsa: underrun")
+	// Capture buffer overflowed (-EPIPE on a capture stream).
+	ErrOverrun = errors.New("alsa: overrun")
+	// Stream was suspended by the system (-ESTRPIPE).
+	ErrSuspended = errors.New("alsa: stream suspended")
+	// Operation would block on a nonblocking stream (-EAGAIN).
+	ErrWouldBlock = errors.New("alsa: operation would block")
+	// Device is in use by another client (-EBUSY).
+	ErrDeviceBusy = errors.New("alsa: device busy")
+	// Device does not exist or was disconnected (-ENOENT, -ENODEV).
+	ErrNoDevice = errors.New("alsa: no such device")
+	// Handle channels or sample format are not set up, or the format has
+	// no whole byte sample size.
+	ErrNotConfigured = errors.New("alsa: stream parameters not configured")
+	// Buffers do not match the channel count or hold partial frames.
+	ErrInvalidBuffer = errors.New("alsa: invalid buffer layout")
+	// Sample format name is not known to ALSA.
+	ErrInvalidFormat = errors.New("alsa: unknown sample format")
+)
+
+// Error describes a failed ALSA call.
+type Error struct {
+	// Operation which failed, e.g. "open" or "set sample rate".
+	Op string
+	// Device name the operation was performed on. May be empty.
+	Device string
+	// Errno reported by ALSA, as a positive value.
+	Errno syscall.Errno
+	// Stream direction, used to tell an underrun from an overrun.
+	stream StreamType
+}
+
+// streamNone is the stream direction of errors of devices other than PCM
+// streams.
+const streamNone = StreamType(C.SND_PCM_STREAM_LAST + 1)
+
+// newError returns Error for the negative ALSA return code.
+func newError(op string, device string, stream StreamType, code C.int) *Error {
+	if code < 0 {
+		code = -code
+	}
+
+	return &Error{
+		Op:     op,
+		Device: device,
+		Errno:  syscall.Errno(code),
+		stream: stream,
+	}
+}
+
+// newDeviceError returns Error for the negative ALSA return code of a
+// device without stream direction, e.g. a mixer or a sequencer.
+func newDeviceError(op string, device string, code C.int) *Error {
+	return newError(op, device, streamNone, code)
+}
+
+// Error returns error description built from the ALSA error string.
+func (e *Error) Error() string {
+	if e.Device == "" {
+		return fmt.Sprintf("alsa: %s: %s", e.Op, strError(-C.int(e.Errno)))
+	}
+
+	return fmt.Sprintf("alsa: %s '%s': %s", e.Op, e.Device, strError(-C.int(e.Errno)))
+}
+
+// Unwrap returns underlying errno, so errors.Is(err, syscall.EPIPE) works.
+func (e *Error) Unwrap() error {
+	return e.Errno
+}
+
+// Is reports whether the error matches one of the package sentinels.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrUnderrun:
+		return e.Errno == syscall.EPIPE && e.stream == StreamTypePlayback
+	case ErrOverrun:
+		return e.Errno == syscall.EPIPE && e.stream == StreamTypeCapture
+	case ErrSuspended:
+		return e.Errno == syscall.ESTRPIPE
+	case ErrWouldBlock:
+		return e.Errno == syscall.EAGAIN
+	case ErrDeviceBusy:
+		return e.Errno == syscall.EBUSY
+	case ErrNoDevice:
+		return e.Errno == syscall.ENOENT || e.Errno == syscall.ENODEV
+	}
+
+	return false
+}
+
+// strError retruns string description of ALSA error by its code.
+func strError(err C.int) string {
+	cErrMsg := C.snd_strerror(err)
+
+	return C.GoString(cErrMsg)
+}
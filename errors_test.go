@@ -0,0 +1,64 @@
+package alsa
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+)
+
+func TestErrorIs(t *testing.T) {
+	underrun := &Error{Op: "write", Device: "default", Errno: syscall.EPIPE, stream: StreamTypePlayback}
+	if !errors.Is(underrun, ErrUnderrun) {
+		t.Errorf("EPIPE on playback is not ErrUnderrun")
+	}
+	if errors.Is(underrun, ErrOverrun) {
+		t.Errorf("EPIPE on playback is ErrOverrun")
+	}
+	if !errors.Is(underrun, syscall.EPIPE) {
+		t.Errorf("Errno is not unwrapped")
+	}
+
+	overrun := &Error{Op: "read", Device: "default", Errno: syscall.EPIPE, stream: StreamTypeCapture}
+	if !errors.Is(overrun, ErrOverrun) {
+		t.Errorf("EPIPE on capture is not ErrOverrun")
+	}
+
+	device := &Error{Op: "read", Device: "hw:0", Errno: syscall.EPIPE, stream: streamNone}
+	if errors.Is(device, ErrUnderrun) || errors.Is(device, ErrOverrun) {
+		t.Errorf("EPIPE without stream is an xrun")
+	}
+
+	tests := []struct {
+		errno    syscall.Errno
+		sentinel error
+	}{
+		{syscall.ESTRPIPE, ErrSuspended},
+		{syscall.EAGAIN, ErrWouldBlock},
+		{syscall.EBUSY, ErrDeviceBusy},
+		{syscall.ENOENT, ErrNoDevice},
+		{syscall.ENODEV, ErrNoDevice},
+	}
+	for _, test := range tests {
+		err := error(&Error{Op: "open", Errno: test.errno})
+		if !errors.Is(err, test.sentinel) {
+			t.Errorf("%v is not %v", test.errno, test.sentinel)
+		}
+	}
+}
+
+func TestErrorAs(t *testing.T) {
+	handle := New()
+	err := handle.Open("no-such-device-for-alsa-test", StreamTypePlayback, ModeBlock)
+	if err == nil {
+		handle.Close()
+		t.Fatalf("Open of unknown device succeeded")
+	}
+
+	var alsaErr *Error
+	if !errors.As(err, &alsaErr) {
+		t.Fatalf("Open error is not *Error. %s", err)
+	}
+	if alsaErr.Op != "open" || alsaErr.Device != "no-such-device-for-alsa-test" {
+		t.Errorf("Unexpected error fields. %+v", alsaErr)
+	}
+}
@@ -0,0 +1,386 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+//
+// int alsaMixerCallback(snd_mixer_t *mixer, unsigned int mask, snd_mixer_elem_t *elem);
+// int alsaMixerElemCallback(snd_mixer_elem_t *elem, unsigned int mask);
+import "C"
+
+import (
+	"context"
+	"sync"
+)
+
+// Control event mask. Tells what changed in an element.
+type CtlEventMask uint
+
+// Control event mask constants.
+const (
+	// Element was removed. All bits are set, so check it with Removed first.
+	CtlEventRemove = C.SND_CTL_EVENT_MASK_REMOVE
+	// Element value changed
+	CtlEventValue = C.SND_CTL_EVENT_MASK_VALUE
+	// Element info changed
+	CtlEventInfo = C.SND_CTL_EVENT_MASK_INFO
+	// Element was added
+	CtlEventAdd = C.SND_CTL_EVENT_MASK_ADD
+	// Element TLV data changed
+	CtlEventTLV = C.SND_CTL_EVENT_MASK_TLV
+)
+
+// Removed reports whether the element was removed.
+func (mask CtlEventMask) Removed() bool {
+	return mask == CtlEventRemove
+}
+
+// CtlEvent reports a change of a control element.
+type CtlEvent struct {
+	Mask CtlEventMask
+	ID   CtlElemID
+}
+
+// MixerEvent reports a change of a simple mixer element.
+type MixerEvent struct {
+	Mask CtlEventMask
+	// Element name and index.
+	Name  string
+	Index int
+	// Changed element, nil when removed.
+	Element *MixerElement
+}
+
+// Events subscribes to element changes of the card and returns them on the
+// channel. The channel is closed when ctx is done or reading events fails.
+// The control must not be closed before the channel is.
+func (ctl *Ctl) Events(ctx context.Context) (<-chan CtlEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	cErr := C.snd_ctl_nonblock(ctl.cCtl, 1)
+	if cErr < 0 {
+		return nil, ctl.newError("set nonblocking mode", cErr)
+	}
+
+	cErr = C.snd_ctl_subscribe_events(ctl.cCtl, 1)
+	if cErr < 0 {
+		C.snd_ctl_nonblock(ctl.cCtl, 0)
+		return nil, ctl.newError("subscribe events", cErr)
+	}
+
+	release := func() {
+		C.snd_ctl_subscribe_events(ctl.cCtl, 0)
+		C.snd_ctl_nonblock(ctl.cCtl, 0)
+	}
+
+	fds, err := ctl.pollFds()
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	var cEvent *C.snd_ctl_event_t
+	cErr = C.snd_ctl_event_malloc(&cEvent)
+	if cErr < 0 {
+		release()
+		return nil, ctl.newError("allocate event", cErr)
+	}
+
+	var cID *C.snd_ctl_elem_id_t
+	cErr = C.snd_ctl_elem_id_malloc(&cID)
+	if cErr < 0 {
+		C.snd_ctl_event_free(cEvent)
+		release()
+		return nil, ctl.newError("allocate element id", cErr)
+	}
+
+	poller, err := NewPoller()
+	if err != nil {
+		C.snd_ctl_elem_id_free(cID)
+		C.snd_ctl_event_free(cEvent)
+		release()
+		return nil, err
+	}
+
+	events := make(chan CtlEvent)
+	go func() {
+		defer close(events)
+		defer release()
+		defer C.snd_ctl_event_free(cEvent)
+		defer C.snd_ctl_elem_id_free(cID)
+		defer wakeOnDone(ctx, poller)()
+
+		for {
+			err := poller.poll(fds, -1)
+			if err == ErrWoken {
+				if ctx.Err() != nil {
+					return
+				}
+				continue
+			}
+			if err != nil {
+				return
+			}
+
+			for {
+				cErr := C.snd_ctl_read(ctl.cCtl, cEvent)
+				if cErr == 0 || cErr == -C.EAGAIN {
+					break
+				}
+				if cErr < 0 {
+					return
+				}
+				if C.snd_ctl_event_get_type(cEvent) != C.SND_CTL_EVENT_ELEM {
+					continue
+				}
+
+				C.snd_ctl_event_elem_get_id(cEvent, cID)
+				event := CtlEvent{
+					Mask: CtlEventMask(C.snd_ctl_event_elem_get_mask(cEvent)),
+					ID:   goCtlElemID(cID),
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// Events watches changes of the mixer elements made by other clients and
+// returns them on the channel. Element values are updated before an event
+// is sent. The channel is closed when ctx is done or handling events
+// fails. The mixer must not be closed before the channel is.
+func (mixer *Mixer) Events(ctx context.Context) (<-chan MixerEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	fds, err := mixer.pollFds()
+	if err != nil {
+		return nil, err
+	}
+
+	poller, err := NewPoller()
+	if err != nil {
+		return nil, err
+	}
+
+	dispatch := &mixerDispatch{mixer: mixer}
+	mixerCallbacks.register(dispatch)
+
+	events := make(chan MixerEvent)
+	go func() {
+		defer close(events)
+		defer mixerCallbacks.unregister(dispatch)
+		defer wakeOnDone(ctx, poller)()
+
+		for {
+			err := poller.poll(fds, -1)
+			if err == ErrWoken {
+				if ctx.Err() != nil {
+					return
+				}
+				continue
+			}
+			if err != nil {
+				return
+			}
+
+			// Element callbacks collect events into pending.
+			if C.snd_mixer_handle_events(mixer.cMixer) < 0 {
+				return
+			}
+
+			for _, event := range mixerCallbacks.take(dispatch) {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// wakeOnDone wakes the poller when ctx is done. Returned function releases
+// the poller once no wakeup is in progress.
+func wakeOnDone(ctx context.Context, poller *Poller) func() {
+	woken := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		poller.Wake()
+		close(woken)
+	})
+
+	return func() {
+		if !stop() {
+			<-woken
+		}
+		poller.Close()
+	}
+}
+
+// pollFds returns poll descriptors of the control.
+func (ctl *Ctl) pollFds() ([]C.struct_pollfd, error) {
+	count := C.snd_ctl_poll_descriptors_count(ctl.cCtl)
+	if count < 0 {
+		return nil, ctl.newError("get poll descriptors count", count)
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	cFds := make([]C.struct_pollfd, count)
+	filled := C.snd_ctl_poll_descriptors(ctl.cCtl, &cFds[0], C.uint(count))
+	if filled < 0 {
+		return nil, ctl.newError("get poll descriptors", filled)
+	}
+
+	return cFds[:filled], nil
+}
+
+// pollFds returns poll descriptors of the mixer.
+func (mixer *Mixer) pollFds() ([]C.struct_pollfd, error) {
+	count := C.snd_mixer_poll_descriptors_count(mixer.cMixer)
+	if count < 0 {
+		return nil, mixer.newError("get poll descriptors count", count)
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	cFds := make([]C.struct_pollfd, count)
+	filled := C.snd_mixer_poll_descriptors(mixer.cMixer, &cFds[0], C.uint(count))
+	if filled < 0 {
+		return nil, mixer.newError("get poll descriptors", filled)
+	}
+
+	return cFds[:filled], nil
+}
+
+// mixerDispatch collects events of one mixer from its callbacks.
+type mixerDispatch struct {
+	mixer   *Mixer
+	pending []MixerEvent
+}
+
+// mixerCallbacks routes mixer callbacks to dispatches by the C pointers.
+var mixerCallbacks = mixerRegistry{
+	mixers: map[*C.snd_mixer_t]*mixerDispatch{},
+	elems:  map[*C.snd_mixer_elem_t]*mixerDispatch{},
+}
+
+// mixerRegistry maps mixers and their elements to dispatches.
+type mixerRegistry struct {
+	sync.Mutex
+	mixers map[*C.snd_mixer_t]*mixerDispatch
+	elems  map[*C.snd_mixer_elem_t]*mixerDispatch
+}
+
+// register installs callbacks on the mixer and all its elements.
+func (registry *mixerRegistry) register(dispatch *mixerDispatch) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	cMixer := dispatch.mixer.cMixer
+	registry.mixers[cMixer] = dispatch
+	C.snd_mixer_set_callback(cMixer, C.snd_mixer_callback_t(C.alsaMixerCallback))
+
+	for cElem := C.snd_mixer_first_elem(cMixer); cElem != nil; cElem = C.snd_mixer_elem_next(cElem) {
+		registry.addElem(dispatch, cElem)
+	}
+}
+
+// unregister removes callbacks of the mixer and its elements.
+func (registry *mixerRegistry) unregister(dispatch *mixerDispatch) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	for cElem, elemDispatch := range registry.elems {
+		if elemDispatch == dispatch {
+			C.snd_mixer_elem_set_callback(cElem, nil)
+			delete(registry.elems, cElem)
+		}
+	}
+
+	C.snd_mixer_set_callback(dispatch.mixer.cMixer, nil)
+	delete(registry.mixers, dispatch.mixer.cMixer)
+}
+
+// addElem installs callback on the element. Registry must be locked.
+func (registry *mixerRegistry) addElem(dispatch *mixerDispatch, cElem *C.snd_mixer_elem_t) {
+	registry.elems[cElem] = dispatch
+	C.snd_mixer_elem_set_callback(cElem, C.snd_mixer_elem_callback_t(C.alsaMixerElemCallback))
+}
+
+// take returns and clears pending events of the dispatch.
+func (registry *mixerRegistry) take(dispatch *mixerDispatch) []MixerEvent {
+	registry.Lock()
+	defer registry.Unlock()
+
+	pending := dispatch.pending
+	dispatch.pending = nil
+
+	return pending
+}
+
+// event returns the mixer event for the element.
+func (dispatch *mixerDispatch) event(mask CtlEventMask, cElem *C.snd_mixer_elem_t) MixerEvent {
+	event := MixerEvent{
+		Mask:  mask,
+		Name:  C.GoString(C.snd_mixer_selem_get_name(cElem)),
+		Index: int(C.snd_mixer_selem_get_index(cElem)),
+	}
+	if !mask.Removed() {
+		event.Element = &MixerElement{mixer: dispatch.mixer, cElem: cElem}
+	}
+
+	return event
+}
+
+// alsaMixerCallback is called by snd_mixer_handle_events for added elements.
+//
+//export alsaMixerCallback
+func alsaMixerCallback(cMixer *C.snd_mixer_t, mask C.uint, cElem *C.snd_mixer_elem_t) C.int {
+	mixerCallbacks.Lock()
+	defer mixerCallbacks.Unlock()
+
+	dispatch := mixerCallbacks.mixers[cMixer]
+	if dispatch == nil || mask&C.SND_CTL_EVENT_MASK_ADD == 0 {
+		return 0
+	}
+
+	mixerCallbacks.addElem(dispatch, cElem)
+	dispatch.pending = append(dispatch.pending, dispatch.event(CtlEventAdd, cElem))
+
+	return 0
+}
+
+// alsaMixerElemCallback is called by snd_mixer_handle_events for changed
+// and removed elements.
+//
+//export alsaMixerElemCallback
+func alsaMixerElemCallback(cElem *C.snd_mixer_elem_t, mask C.uint) C.int {
+	mixerCallbacks.Lock()
+	defer mixerCallbacks.Unlock()
+
+	dispatch := mixerCallbacks.elems[cElem]
+	if dispatch == nil {
+		return 0
+	}
+
+	event := dispatch.event(CtlEventMask(mask), cElem)
+	if event.Mask.Removed() {
+		delete(mixerCallbacks.elems, cElem)
+	}
+	dispatch.pending = append(dispatch.pending, event)
+
+	return 0
+}
@@ -0,0 +1,89 @@
+package alsa
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCtlEvents(t *testing.T) {
+	ctl, err := OpenCtl("default")
+	if err != nil {
+		t.Fatalf("OpenCtl failed. %s", err)
+	}
+	defer ctl.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := ctl.Events(ctx)
+	if err != nil {
+		cancel()
+		t.Fatalf("Events failed. %s", err)
+	}
+
+	// Change a volume from another client.
+	mixer, err := OpenMixer("default")
+	if err != nil {
+		cancel()
+		t.Fatalf("OpenMixer failed. %s", err)
+	}
+	defer mixer.Close()
+
+	changed := false
+	for _, elem := range mixer.Elements() {
+		if !elem.HasVolume(StreamTypePlayback) {
+			continue
+		}
+		channel := elem.Channels(StreamTypePlayback)[0]
+		volume, err := elem.Volume(StreamTypePlayback, channel)
+		if err != nil {
+			continue
+		}
+		min, max, _ := elem.VolumeRange(StreamTypePlayback)
+		next := min
+		if volume == min {
+			next = max
+		}
+		if elem.SetVolume(StreamTypePlayback, channel, next) == nil {
+			defer elem.SetVolume(StreamTypePlayback, channel, volume)
+			changed = true
+			break
+		}
+	}
+
+	if changed {
+		select {
+		case event := <-events:
+			if event.Mask.Removed() || event.Mask&CtlEventValue == 0 {
+				t.Errorf("Unexpected event mask %x for %v", event.Mask, event.ID)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("No event after volume change")
+		}
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestMixerEventsCancel(t *testing.T) {
+	mixer, err := OpenMixer("default")
+	if err != nil {
+		t.Fatalf("OpenMixer failed. %s", err)
+	}
+	defer mixer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := mixer.Events(ctx)
+	if err != nil {
+		cancel()
+		t.Fatalf("Events failed. %s", err)
+	}
+
+	cancel()
+	select {
+	case <-events:
+	case <-time.After(time.Second):
+		t.Fatalf("Events channel not closed after cancel")
+	}
+}
@@ -0,0 +1,109 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+import "C"
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unsafe"
+)
+
+// String returns ALSA name of the sample format, e.g. "S16_LE".
+func (format SampleFormat) String() string {
+	cName := C.snd_pcm_format_name(C.snd_pcm_format_t(format))
+	if cName == nil {
+		return fmt.Sprintf("SampleFormat(%d)", int(format))
+	}
+
+	return C.GoString(cName)
+}
+
+// Description returns human readable description of the sample format.
+func (format SampleFormat) Description() string {
+	cDescription := C.snd_pcm_format_description(C.snd_pcm_format_t(format))
+	if cDescription == nil {
+		return ""
+	}
+
+	return C.GoString(cDescription)
+}
+
+// ParseSampleFormat returns sample format by its ALSA name, e.g. "S16_LE"
+// or "FLOAT". Names are case insensitive.
+func ParseSampleFormat(name string) (SampleFormat, error) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	format := C.snd_pcm_format_value(cName)
+	if format == C.SND_PCM_FORMAT_UNKNOWN {
+		return SampleFormatUnknown, fmt.Errorf("%w '%s'", ErrInvalidFormat, name)
+	}
+
+	return SampleFormat(format), nil
+}
+
+// Width returns number of significant bits in a sample.
+// Zero for formats without fixed sample width.
+func (format SampleFormat) Width() int {
+	width := C.snd_pcm_format_width(C.snd_pcm_format_t(format))
+	if width < 0 {
+		return 0
+	}
+
+	return int(width)
+}
+
+// PhysicalWidth returns number of bits a sample occupies in memory.
+// Zero for formats without fixed sample width.
+func (format SampleFormat) PhysicalWidth() int {
+	width := C.snd_pcm_format_physical_width(C.snd_pcm_format_t(format))
+	if width < 0 {
+		return 0
+	}
+
+	return int(width)
+}
+
+// IsSigned reports whether the format is a signed linear format. Floating
+// point formats are signed too, though ALSA does not list them as such.
+func (format SampleFormat) IsSigned() bool {
+	if format.IsFloat() {
+		return true
+	}
+
+	return C.snd_pcm_format_signed(C.snd_pcm_format_t(format)) > 0
+}
+
+// IsFloat reports whether the format is a floating point format.
+func (format SampleFormat) IsFloat() bool {
+	return C.snd_pcm_format_float(C.snd_pcm_format_t(format)) > 0
+}
+
+// ByteOrder returns byte order of the format samples, or nil for formats
+// where it does not apply, like 8 bit or compressed ones.
+func (format SampleFormat) ByteOrder() binary.ByteOrder {
+	switch C.snd_pcm_format_little_endian(C.snd_pcm_format_t(format)) {
+	case 1:
+		return binary.LittleEndian
+	case 0:
+		return binary.BigEndian
+	}
+
+	return nil
+}
+
+// Silence returns bytes of one silent sample, e.g. 0x80 for U8.
+// Nil for formats without whole byte samples.
+func (format SampleFormat) Silence() []byte {
+	size := format.PhysicalWidth() / 8
+	if size == 0 {
+		return nil
+	}
+
+	// Pattern is returned ready to be stored in CPU byte order.
+	var pattern [8]byte
+	binary.NativeEndian.PutUint64(pattern[:], uint64(C.snd_pcm_format_silence_64(C.snd_pcm_format_t(format))))
+
+	return pattern[:size]
+}
@@ -0,0 +1,79 @@
+package alsa
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestSampleFormat(t *testing.T) {
+	tests := []struct {
+		format        SampleFormat
+		name          string
+		width         int
+		physicalWidth int
+		signed        bool
+		float         bool
+		byteOrder     binary.ByteOrder
+		silence       []byte
+	}{
+		{SampleFormatU8, "U8", 8, 8, false, false, nil, []byte{0x80}},
+		{SampleFormatS16LE, "S16_LE", 16, 16, true, false, binary.LittleEndian, []byte{0, 0}},
+		{SampleFormatU16BE, "U16_BE", 16, 16, false, false, binary.BigEndian, []byte{0x80, 0}},
+		{SampleFormatS24_3LE, "S24_3LE", 24, 24, true, false, binary.LittleEndian, []byte{0, 0, 0}},
+		{SampleFormatS24LE, "S24_LE", 24, 32, true, false, binary.LittleEndian, []byte{0, 0, 0, 0}},
+		{SampleFormatFloatLE, "FLOAT_LE", 32, 32, true, true, binary.LittleEndian, []byte{0, 0, 0, 0}},
+		{SampleFormatFloat64BE, "FLOAT64_BE", 64, 64, true, true, binary.BigEndian, make([]byte, 8)},
+	}
+
+	for _, test := range tests {
+		if name := test.format.String(); name != test.name {
+			t.Errorf("%s: String returned %s", test.name, name)
+		}
+		if width := test.format.Width(); width != test.width {
+			t.Errorf("%s: Width %d, expected %d", test.name, width, test.width)
+		}
+		if width := test.format.PhysicalWidth(); width != test.physicalWidth {
+			t.Errorf("%s: PhysicalWidth %d, expected %d", test.name, width, test.physicalWidth)
+		}
+		if test.format.IsSigned() != test.signed {
+			t.Errorf("%s: IsSigned is not %v", test.name, test.signed)
+		}
+		if test.format.IsFloat() != test.float {
+			t.Errorf("%s: IsFloat is not %v", test.name, test.float)
+		}
+		if test.format.ByteOrder() != test.byteOrder {
+			t.Errorf("%s: ByteOrder is not %v", test.name, test.byteOrder)
+		}
+		if silence := test.format.Silence(); !bytes.Equal(silence, test.silence) {
+			t.Errorf("%s: Silence %v, expected %v", test.name, silence, test.silence)
+		}
+
+		format, err := ParseSampleFormat(test.name)
+		if err != nil {
+			t.Errorf("ParseSampleFormat(%s) failed. %s", test.name, err)
+		} else if format != test.format {
+			t.Errorf("ParseSampleFormat(%s) returned %s", test.name, format)
+		}
+	}
+
+	if _, err := ParseSampleFormat("NO_SUCH_FORMAT"); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("ParseSampleFormat of unknown name returned %v", err)
+	}
+}
+
+func TestSampleSize(t *testing.T) {
+	handle := New()
+	handle.Channels = 2
+
+	handle.SampleFormat = SampleFormatFloat64LE
+	if size := handle.FrameSize(); size != 16 {
+		t.Errorf("FLOAT64_LE frame size %d, expected 16", size)
+	}
+
+	handle.SampleFormat = SampleFormatS20_3LE
+	if size := handle.FrameSize(); size != 6 {
+		t.Errorf("S20_3LE frame size %d, expected 6", size)
+	}
+}
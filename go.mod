@@ -1,3 +1,3 @@
 module github.com/thinkontrol/alsa-cgo
 
-go 1.19
+go 1.21
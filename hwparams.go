@@ -0,0 +1,435 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+import "C"
+
+// Access type of the stream buffer. Unlike ALSA, the zero value is RW
+// interleaved access, so a zero Handle uses Read and Write.
+type Access int
+
+// Access type constants.
+const (
+	// snd_pcm_readi/snd_pcm_writei access
+	AccessRWInterleaved Access = iota
+	// snd_pcm_readn/snd_pcm_writen access
+	AccessRWNonInterleaved
+	// MMAP access with simple interleaved channels
+	AccessMmapInterleaved
+	// MMAP access with simple non interleaved channels
+	AccessMmapNonInterleaved
+	// MMAP access with complex placement
+	AccessMmapComplex
+)
+
+// cAccesses maps access types to ALSA values.
+var cAccesses = [...]C.snd_pcm_access_t{
+	AccessRWInterleaved:      C.SND_PCM_ACCESS_RW_INTERLEAVED,
+	AccessRWNonInterleaved:   C.SND_PCM_ACCESS_RW_NONINTERLEAVED,
+	AccessMmapInterleaved:    C.SND_PCM_ACCESS_MMAP_INTERLEAVED,
+	AccessMmapNonInterleaved: C.SND_PCM_ACCESS_MMAP_NONINTERLEAVED,
+	AccessMmapComplex:        C.SND_PCM_ACCESS_MMAP_COMPLEX,
+}
+
+// String returns ALSA name of the access type.
+func (access Access) String() string {
+	return C.GoString(C.snd_pcm_access_name(access.cAccess()))
+}
+
+// cAccess returns ALSA value of the access type.
+func (access Access) cAccess() C.snd_pcm_access_t {
+	if access < 0 || int(access) >= len(cAccesses) {
+		return C.SND_PCM_ACCESS_LAST + 1
+	}
+
+	return cAccesses[access]
+}
+
+// goAccess returns access type of the ALSA value.
+func goAccess(cAccess C.snd_pcm_access_t) Access {
+	for access, value := range cAccesses {
+		if value == cAccess {
+			return Access(access)
+		}
+	}
+
+	return Access(-1)
+}
+
+// HwParams is a hardware configuration space of the stream.
+// Every setter narrows the space; Apply installs the result on the device.
+type HwParams struct {
+	handle  *Handle
+	cParams *C.snd_pcm_hw_params_t
+}
+
+// HwParams returns the full configuration space of the opened stream.
+// Returned value must be released with Free.
+func (handle *Handle) HwParams() (*HwParams, error) {
+	params := &HwParams{handle: handle}
+
+	err := C.snd_pcm_hw_params_malloc(&params.cParams)
+	if err < 0 {
+		return nil, handle.newError("allocate hardware parameters", err)
+	}
+
+	err = C.snd_pcm_hw_params_any(handle.cHandle, params.cParams)
+	if err < 0 {
+		C.snd_pcm_hw_params_free(params.cParams)
+		return nil, handle.newError("initialize hardware parameters", err)
+	}
+
+	return params, nil
+}
+
+// Free releases the configuration space.
+func (params *HwParams) Free() {
+	if params.cParams != nil {
+		C.snd_pcm_hw_params_free(params.cParams)
+		params.cParams = nil
+	}
+}
+
+// Apply installs the configuration on the stream and writes values
+// actually chosen by ALSA back to the handle.
+func (params *HwParams) Apply() error {
+	handle := params.handle
+
+	err := C.snd_pcm_hw_params(handle.cHandle, params.cParams)
+	if err < 0 {
+		return handle.newError("set hardware parameters", err)
+	}
+
+	var access C.snd_pcm_access_t
+	err = C.snd_pcm_hw_params_get_access(params.cParams, &access)
+	if err < 0 {
+		return handle.newError("get access type", err)
+	}
+
+	var format C.snd_pcm_format_t
+	err = C.snd_pcm_hw_params_get_format(params.cParams, &format)
+	if err < 0 {
+		return handle.newError("get sample format", err)
+	}
+
+	var rate, channels, periods C.uint
+	var dir C.int
+	err = C.snd_pcm_hw_params_get_rate(params.cParams, &rate, &dir)
+	if err < 0 {
+		return handle.newError("get sample rate", err)
+	}
+
+	err = C.snd_pcm_hw_params_get_channels(params.cParams, &channels)
+	if err < 0 {
+		return handle.newError("get number of channels", err)
+	}
+
+	var periodSize, bufferSize C.snd_pcm_uframes_t
+	err = C.snd_pcm_hw_params_get_period_size(params.cParams, &periodSize, &dir)
+	if err < 0 {
+		return handle.newError("get period size", err)
+	}
+
+	err = C.snd_pcm_hw_params_get_buffer_size(params.cParams, &bufferSize)
+	if err < 0 {
+		return handle.newError("get buffer size", err)
+	}
+
+	// Periods are not exact when the buffer is not a whole number of
+	// periods, but the parameters are installed already.
+	err = C.snd_pcm_hw_params_get_periods(params.cParams, &periods, &dir)
+	if err < 0 && periodSize > 0 {
+		periods = C.uint(bufferSize / periodSize)
+	}
+
+	// Fields are updated together once all values are read.
+	handle.Access = goAccess(access)
+	handle.SampleFormat = SampleFormat(format)
+	handle.SampleRate = int(rate)
+	handle.Channels = int(channels)
+	handle.Periods = int(periods)
+	handle.PeriodSize = int(periodSize)
+	handle.Buffersize = int(bufferSize)
+
+	return nil
+}
+
+// SetAccess restricts the space to the access type.
+func (params *HwParams) SetAccess(access Access) error {
+	err := C.snd_pcm_hw_params_set_access(params.handle.cHandle, params.cParams, access.cAccess())
+	if err < 0 {
+		return params.handle.newError("set access type", err)
+	}
+
+	return nil
+}
+
+// TestAccess reports whether the access type is available in the space.
+func (params *HwParams) TestAccess(access Access) bool {
+	return C.snd_pcm_hw_params_test_access(params.handle.cHandle, params.cParams, access.cAccess()) == 0
+}
+
+// SetFormat restricts the space to the sample format.
+func (params *HwParams) SetFormat(format SampleFormat) error {
+	err := C.snd_pcm_hw_params_set_format(params.handle.cHandle, params.cParams, C.snd_pcm_format_t(format))
+	if err < 0 {
+		return params.handle.newError("set sample format", err)
+	}
+
+	return nil
+}
+
+// TestFormat reports whether the sample format is available in the space.
+func (params *HwParams) TestFormat(format SampleFormat) bool {
+	return C.snd_pcm_hw_params_test_format(params.handle.cHandle, params.cParams, C.snd_pcm_format_t(format)) == 0
+}
+
+// SetChannels restricts the space to exact channels count.
+func (params *HwParams) SetChannels(channels int) error {
+	err := C.snd_pcm_hw_params_set_channels(params.handle.cHandle, params.cParams, C.uint(channels))
+	if err < 0 {
+		return params.handle.newError("set number of channels", err)
+	}
+
+	return nil
+}
+
+// SetChannelsNear restricts the space to channels count nearest to the
+// requested one and returns the chosen value.
+func (params *HwParams) SetChannelsNear(channels int) (int, error) {
+	cChannels := C.uint(channels)
+	err := C.snd_pcm_hw_params_set_channels_near(params.handle.cHandle, params.cParams, &cChannels)
+	if err < 0 {
+		return 0, params.handle.newError("set number of channels", err)
+	}
+
+	return int(cChannels), nil
+}
+
+// TestChannels reports whether the channels count is available in the space.
+func (params *HwParams) TestChannels(channels int) bool {
+	return C.snd_pcm_hw_params_test_channels(params.handle.cHandle, params.cParams, C.uint(channels)) == 0
+}
+
+// ChannelsRange returns minimum and maximum channels count in the space.
+func (params *HwParams) ChannelsRange() (min, max int, err error) {
+	var cMin, cMax C.uint
+
+	cErr := C.snd_pcm_hw_params_get_channels_min(params.cParams, &cMin)
+	if cErr < 0 {
+		return 0, 0, params.handle.newError("get minimum number of channels", cErr)
+	}
+
+	cErr = C.snd_pcm_hw_params_get_channels_max(params.cParams, &cMax)
+	if cErr < 0 {
+		return 0, 0, params.handle.newError("get maximum number of channels", cErr)
+	}
+
+	return int(cMin), int(cMax), nil
+}
+
+// SetRate restricts the space to exact sample rate in Hz.
+func (params *HwParams) SetRate(rate int) error {
+	err := C.snd_pcm_hw_params_set_rate(params.handle.cHandle, params.cParams, C.uint(rate), 0)
+	if err < 0 {
+		return params.handle.newError("set sample rate", err)
+	}
+
+	return nil
+}
+
+// SetRateNear restricts the space to sample rate nearest to the requested
+// one and returns the chosen value.
+func (params *HwParams) SetRateNear(rate int) (int, error) {
+	cRate := C.uint(rate)
+	err := C.snd_pcm_hw_params_set_rate_near(params.handle.cHandle, params.cParams, &cRate, nil)
+	if err < 0 {
+		return 0, params.handle.newError("set sample rate", err)
+	}
+
+	return int(cRate), nil
+}
+
+// SetRateResample enables or disables software resampling. Disabled
+// resampling restricts the space to rates supported by the hardware.
+func (params *HwParams) SetRateResample(resample bool) error {
+	err := C.snd_pcm_hw_params_set_rate_resample(params.handle.cHandle, params.cParams, C.uint(cBool(resample)))
+	if err < 0 {
+		return params.handle.newError("set rate resampling", err)
+	}
+
+	return nil
+}
+
+// TestRate reports whether the sample rate is available in the space.
+func (params *HwParams) TestRate(rate int) bool {
+	return C.snd_pcm_hw_params_test_rate(params.handle.cHandle, params.cParams, C.uint(rate), 0) == 0
+}
+
+// RateRange returns minimum and maximum sample rate in the space.
+func (params *HwParams) RateRange() (min, max int, err error) {
+	var cMin, cMax C.uint
+	var dir C.int
+
+	cErr := C.snd_pcm_hw_params_get_rate_min(params.cParams, &cMin, &dir)
+	if cErr < 0 {
+		return 0, 0, params.handle.newError("get minimum sample rate", cErr)
+	}
+
+	cErr = C.snd_pcm_hw_params_get_rate_max(params.cParams, &cMax, &dir)
+	if cErr < 0 {
+		return 0, 0, params.handle.newError("get maximum sample rate", cErr)
+	}
+
+	return int(cMin), int(cMax), nil
+}
+
+// SetPeriodSize restricts the space to exact period size in frames.
+func (params *HwParams) SetPeriodSize(frames int) error {
+	err := C.snd_pcm_hw_params_set_period_size(params.handle.cHandle, params.cParams, C.snd_pcm_uframes_t(frames), 0)
+	if err < 0 {
+		return params.handle.newError("set period size", err)
+	}
+
+	return nil
+}
+
+// SetPeriodSizeNear restricts the space to period size nearest to the
+// requested one and returns the chosen value in frames.
+func (params *HwParams) SetPeriodSizeNear(frames int) (int, error) {
+	cFrames := C.snd_pcm_uframes_t(frames)
+	var dir C.int
+	err := C.snd_pcm_hw_params_set_period_size_near(params.handle.cHandle, params.cParams, &cFrames, &dir)
+	if err < 0 {
+		return 0, params.handle.newError("set period size", err)
+	}
+
+	return int(cFrames), nil
+}
+
+// TestPeriodSize reports whether the period size is available in the space.
+func (params *HwParams) TestPeriodSize(frames int) bool {
+	return C.snd_pcm_hw_params_test_period_size(params.handle.cHandle, params.cParams, C.snd_pcm_uframes_t(frames), 0) == 0
+}
+
+// PeriodSizeRange returns minimum and maximum period size in frames.
+func (params *HwParams) PeriodSizeRange() (min, max int, err error) {
+	var cMin, cMax C.snd_pcm_uframes_t
+	var dir C.int
+
+	cErr := C.snd_pcm_hw_params_get_period_size_min(params.cParams, &cMin, &dir)
+	if cErr < 0 {
+		return 0, 0, params.handle.newError("get minimum period size", cErr)
+	}
+
+	cErr = C.snd_pcm_hw_params_get_period_size_max(params.cParams, &cMax, &dir)
+	if cErr < 0 {
+		return 0, 0, params.handle.newError("get maximum period size", cErr)
+	}
+
+	return int(cMin), int(cMax), nil
+}
+
+// SetPeriods restricts the space to exact number of periods in the buffer.
+func (params *HwParams) SetPeriods(periods int) error {
+	err := C.snd_pcm_hw_params_set_periods(params.handle.cHandle, params.cParams, C.uint(periods), 0)
+	if err < 0 {
+		return params.handle.newError("set number of periods", err)
+	}
+
+	return nil
+}
+
+// SetPeriodsNear restricts the space to number of periods nearest to the
+// requested one and returns the chosen value.
+func (params *HwParams) SetPeriodsNear(periods int) (int, error) {
+	cPeriods := C.uint(periods)
+	var dir C.int
+	err := C.snd_pcm_hw_params_set_periods_near(params.handle.cHandle, params.cParams, &cPeriods, &dir)
+	if err < 0 {
+		return 0, params.handle.newError("set number of periods", err)
+	}
+
+	return int(cPeriods), nil
+}
+
+// TestPeriods reports whether the number of periods is available in the space.
+func (params *HwParams) TestPeriods(periods int) bool {
+	return C.snd_pcm_hw_params_test_periods(params.handle.cHandle, params.cParams, C.uint(periods), 0) == 0
+}
+
+// PeriodsRange returns minimum and maximum number of periods in the buffer.
+func (params *HwParams) PeriodsRange() (min, max int, err error) {
+	var cMin, cMax C.uint
+	var dir C.int
+
+	cErr := C.snd_pcm_hw_params_get_periods_min(params.cParams, &cMin, &dir)
+	if cErr < 0 {
+		return 0, 0, params.handle.newError("get minimum number of periods", cErr)
+	}
+
+	cErr = C.snd_pcm_hw_params_get_periods_max(params.cParams, &cMax, &dir)
+	if cErr < 0 {
+		return 0, 0, params.handle.newError("get maximum number of periods", cErr)
+	}
+
+	return int(cMin), int(cMax), nil
+}
+
+// SetBufferSize restricts the space to exact buffer size in frames.
+func (params *HwParams) SetBufferSize(frames int) error {
+	err := C.snd_pcm_hw_params_set_buffer_size(params.handle.cHandle, params.cParams, C.snd_pcm_uframes_t(frames))
+	if err < 0 {
+		return params.handle.newError("set buffer size", err)
+	}
+
+	return nil
+}
+
+// SetBufferSizeNear restricts the space to buffer size nearest to the
+// requested one and returns the chosen value in frames.
+func (params *HwParams) SetBufferSizeNear(frames int) (int, error) {
+	cFrames := C.snd_pcm_uframes_t(frames)
+	err := C.snd_pcm_hw_params_set_buffer_size_near(params.handle.cHandle, params.cParams, &cFrames)
+	if err < 0 {
+		return 0, params.handle.newError("set buffer size", err)
+	}
+
+	return int(cFrames), nil
+}
+
+// TestBufferSize reports whether the buffer size is available in the space.
+func (params *HwParams) TestBufferSize(frames int) bool {
+	return C.snd_pcm_hw_params_test_buffer_size(params.handle.cHandle, params.cParams, C.snd_pcm_uframes_t(frames)) == 0
+}
+
+// BufferSizeRange returns minimum and maximum buffer size in frames.
+func (params *HwParams) BufferSizeRange() (min, max int, err error) {
+	var cMin, cMax C.snd_pcm_uframes_t
+
+	cErr := C.snd_pcm_hw_params_get_buffer_size_min(params.cParams, &cMin)
+	if cErr < 0 {
+		return 0, 0, params.handle.newError("get minimum buffer size", cErr)
+	}
+
+	cErr = C.snd_pcm_hw_params_get_buffer_size_max(params.cParams, &cMax)
+	if cErr < 0 {
+		return 0, 0, params.handle.newError("get maximum buffer size", cErr)
+	}
+
+	return int(cMin), int(cMax), nil
+}
+
+// SupportsAudioTimestampType reports whether the hardware provides audio
+// timestamps of the type.
+func (params *HwParams) SupportsAudioTimestampType(audioType AudioTimestampType) bool {
+	return C.snd_pcm_hw_params_supports_audio_ts_type(params.cParams, C.int(audioType)) != 0
+}
+
+// cBool converts Go boolean to C 0/1 flag.
+func cBool(value bool) C.int {
+	if value {
+		return 1
+	}
+
+	return 0
+}
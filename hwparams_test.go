@@ -0,0 +1,90 @@
+package alsa
+
+import (
+	"testing"
+)
+
+func TestHwParams(t *testing.T) {
+	handle := New()
+	err := handle.Open("default", StreamTypePlayback, ModeBlock)
+	if err != nil {
+		t.Fatalf("Open failed. %s", err)
+	}
+	defer handle.Close()
+
+	params, err := handle.HwParams()
+	if err != nil {
+		t.Fatalf("HwParams failed. %s", err)
+	}
+	defer params.Free()
+
+	minRate, maxRate, err := params.RateRange()
+	if err != nil {
+		t.Fatalf("RateRange failed. %s", err)
+	}
+	if minRate > maxRate {
+		t.Errorf("Invalid rate range %d-%d", minRate, maxRate)
+	}
+
+	if err = params.SetAccess(AccessRWInterleaved); err != nil {
+		t.Fatalf("SetAccess failed. %s", err)
+	}
+	if err = params.SetFormat(SampleFormatS16LE); err != nil {
+		t.Fatalf("SetFormat failed. %s", err)
+	}
+	if _, err = params.SetChannelsNear(2); err != nil {
+		t.Fatalf("SetChannelsNear failed. %s", err)
+	}
+	rate, err := params.SetRateNear(44100)
+	if err != nil {
+		t.Fatalf("SetRateNear failed. %s", err)
+	}
+
+	err = params.Apply()
+	if err != nil {
+		t.Fatalf("Apply failed. %s", err)
+	}
+
+	if handle.SampleRate != rate {
+		t.Errorf("Handle sample rate %d, negotiated %d", handle.SampleRate, rate)
+	}
+	if handle.PeriodSize <= 0 || handle.Buffersize < handle.PeriodSize {
+		t.Errorf("Invalid period size %d or buffer size %d", handle.PeriodSize, handle.Buffersize)
+	}
+}
+
+func TestAccessValues(t *testing.T) {
+	var access Access
+	if access.String() != "RW_INTERLEAVED" {
+		t.Errorf("Zero access is %s", access)
+	}
+	for _, access := range []Access{AccessRWInterleaved, AccessRWNonInterleaved,
+		AccessMmapInterleaved, AccessMmapNonInterleaved, AccessMmapComplex} {
+		if goAccess(access.cAccess()) != access {
+			t.Errorf("Access %d does not round trip", access)
+		}
+	}
+}
+
+func TestZeroHandle(t *testing.T) {
+	var handle Handle
+	err := handle.OpenWithConfig("out", "pcm.out { type null }", StreamTypePlayback, ModeBlock)
+	if err != nil {
+		t.Fatalf("OpenWithConfig failed. %s", err)
+	}
+	defer handle.Close()
+
+	handle.SampleFormat = SampleFormatS16LE
+	handle.SampleRate = 44100
+	handle.Channels = 2
+	if err = handle.ApplyHwParams(); err != nil {
+		t.Fatalf("ApplyHwParams failed. %s", err)
+	}
+	if handle.Access != AccessRWInterleaved {
+		t.Errorf("Negotiated access %s", handle.Access)
+	}
+
+	if _, err = handle.Write(make([]byte, 64)); err != nil {
+		t.Fatalf("Write failed. %s", err)
+	}
+}
@@ -0,0 +1,120 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+import "C"
+
+// PCM device class.
+type PCMClass C.snd_pcm_class_t
+
+// PCM class constants.
+const (
+	// Standard mono or stereo device
+	PCMClassGeneric = C.SND_PCM_CLASS_GENERIC
+	// Multichannel device
+	PCMClassMulti = C.SND_PCM_CLASS_MULTI
+	// Software modem device
+	PCMClassModem = C.SND_PCM_CLASS_MODEM
+	// Digitizer device
+	PCMClassDigitizer = C.SND_PCM_CLASS_DIGITIZER
+)
+
+// PCM device subclass.
+type PCMSubclass C.snd_pcm_subclass_t
+
+// PCM subclass constants.
+const (
+	// Subdevices are mixed together
+	PCMSubclassGenericMix = C.SND_PCM_SUBCLASS_GENERIC_MIX
+	// Multichannel subdevices are mixed together
+	PCMSubclassMultiMix = C.SND_PCM_SUBCLASS_MULTI_MIX
+)
+
+// Info describes the sound card device behind the stream.
+type Info struct {
+	// Card index, negative for devices not bound to a card.
+	Card int
+	// Device index on the card.
+	Device int
+	// Subdevice index.
+	Subdevice int
+	// Device identifier.
+	ID string
+	// Device name.
+	Name string
+	// Subdevice name.
+	SubdeviceName string
+	// Device class.
+	Class PCMClass
+	// Device subclass.
+	Subclass PCMSubclass
+}
+
+// Info returns information about the device the stream was opened on.
+func (handle *Handle) Info() (*Info, error) {
+	var cInfo *C.snd_pcm_info_t
+	err := C.snd_pcm_info_malloc(&cInfo)
+	if err < 0 {
+		return nil, handle.newError("allocate info", err)
+	}
+	defer C.snd_pcm_info_free(cInfo)
+
+	err = C.snd_pcm_info(handle.cHandle, cInfo)
+	if err < 0 {
+		return nil, handle.newError("get info", err)
+	}
+
+	return &Info{
+		Card:          int(C.snd_pcm_info_get_card(cInfo)),
+		Device:        int(C.snd_pcm_info_get_device(cInfo)),
+		Subdevice:     int(C.snd_pcm_info_get_subdevice(cInfo)),
+		ID:            C.GoString(C.snd_pcm_info_get_id(cInfo)),
+		Name:          C.GoString(C.snd_pcm_info_get_name(cInfo)),
+		SubdeviceName: C.GoString(C.snd_pcm_info_get_subdevice_name(cInfo)),
+		Class:         PCMClass(C.snd_pcm_info_get_class(cInfo)),
+		Subclass:      PCMSubclass(C.snd_pcm_info_get_subclass(cInfo)),
+	}, nil
+}
+
+// Dump returns human readable description of the stream plugin chain and
+// its current setup, as printed by aplay -v.
+func (handle *Handle) Dump() (string, error) {
+	return handle.dump("dump", func(out *C.snd_output_t) C.int {
+		return C.snd_pcm_dump(handle.cHandle, out)
+	})
+}
+
+// DumpHwSetup returns human readable description of the applied hardware
+// parameters.
+func (handle *Handle) DumpHwSetup() (string, error) {
+	return handle.dump("dump hardware setup", func(out *C.snd_output_t) C.int {
+		return C.snd_pcm_dump_hw_setup(handle.cHandle, out)
+	})
+}
+
+// DumpSwSetup returns human readable description of the applied software
+// parameters.
+func (handle *Handle) DumpSwSetup() (string, error) {
+	return handle.dump("dump software setup", func(out *C.snd_output_t) C.int {
+		return C.snd_pcm_dump_sw_setup(handle.cHandle, out)
+	})
+}
+
+// dump captures output of the dump function into a string.
+func (handle *Handle) dump(op string, dump func(out *C.snd_output_t) C.int) (string, error) {
+	var out *C.snd_output_t
+	err := C.snd_output_buffer_open(&out)
+	if err < 0 {
+		return "", handle.newError("open output buffer", err)
+	}
+	defer C.snd_output_close(out)
+
+	err = dump(out)
+	if err < 0 {
+		return "", handle.newError(op, err)
+	}
+
+	var buf *C.char
+	size := C.snd_output_buffer_string(out, &buf)
+
+	return C.GoStringN(buf, C.int(size)), nil
+}
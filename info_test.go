@@ -0,0 +1,55 @@
+package alsa
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInfoAndDump(t *testing.T) {
+	handle := New()
+	err := handle.Open("default", StreamTypePlayback, ModeBlock)
+	if err != nil {
+		t.Fatalf("Open failed. %s", err)
+	}
+	defer handle.Close()
+
+	handle.SampleFormat = SampleFormatS16LE
+	handle.SampleRate = 44100
+	handle.Channels = 2
+	err = handle.ApplyHwParams()
+	if err != nil {
+		t.Fatalf("ApplyHwParams failed. %s", err)
+	}
+
+	info, err := handle.Info()
+	if err != nil {
+		t.Fatalf("Info failed. %s", err)
+	}
+	if info.Device < 0 || info.Subdevice < 0 {
+		t.Errorf("Invalid device %d or subdevice %d", info.Device, info.Subdevice)
+	}
+
+	dump, err := handle.DumpHwSetup()
+	if err != nil {
+		t.Fatalf("DumpHwSetup failed. %s", err)
+	}
+	if !strings.Contains(dump, "S16_LE") {
+		t.Errorf("Hardware setup dump lacks the format: %q", dump)
+	}
+
+	dump, err = handle.DumpSwSetup()
+	if err != nil {
+		t.Fatalf("DumpSwSetup failed. %s", err)
+	}
+	if !strings.Contains(dump, "start_threshold") {
+		t.Errorf("Software setup dump lacks the start threshold: %q", dump)
+	}
+
+	dump, err = handle.Dump()
+	if err != nil {
+		t.Fatalf("Dump failed. %s", err)
+	}
+	if dump == "" {
+		t.Errorf("Empty dump")
+	}
+}
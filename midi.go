@@ -0,0 +1,130 @@
+package alsa
+
+// MidiMessage is a complete MIDI message with its status byte. SysEx
+// messages include the starting 0xF0 and ending 0xF7 bytes.
+type MidiMessage []byte
+
+// Status returns the status byte of the message.
+func (msg MidiMessage) Status() byte {
+	if len(msg) == 0 {
+		return 0
+	}
+
+	return msg[0]
+}
+
+// Channel returns the channel 0-15 of a channel message, or -1 for system
+// messages.
+func (msg MidiMessage) Channel() int {
+	status := msg.Status()
+	if status < 0x80 || status >= 0xf0 {
+		return -1
+	}
+
+	return int(status & 0x0f)
+}
+
+// IsSysEx reports whether the message is a system exclusive message.
+func (msg MidiMessage) IsSysEx() bool {
+	return msg.Status() == 0xf0
+}
+
+// MidiParser splits a MIDI byte stream into messages. Running status,
+// real-time bytes interleaved with other messages and SysEx split across
+// several reads are handled. The zero value is ready to use.
+type MidiParser struct {
+	// Running status, zero when not set.
+	status byte
+	// Message being collected.
+	msg []byte
+	// Data bytes still missing from the message.
+	need int
+	// Collecting SysEx.
+	sysex bool
+}
+
+// Parse parses next bytes of the stream and returns completed messages.
+// Returned messages do not share memory with the parser.
+func (parser *MidiParser) Parse(data []byte) []MidiMessage {
+	var msgs []MidiMessage
+
+	for _, b := range data {
+		switch {
+		case b >= 0xf8:
+			// Real-time messages may appear anywhere and keep the state.
+			msgs = append(msgs, MidiMessage{b})
+
+		case b == 0xf7:
+			if parser.sysex {
+				msgs = append(msgs, append(MidiMessage(parser.msg), b))
+			}
+			parser.reset(0)
+
+		case b == 0xf0:
+			parser.reset(0)
+			parser.msg = []byte{b}
+			parser.sysex = true
+
+		case b >= 0x80:
+			// Status byte; an unterminated SysEx is dropped. System common
+			// messages cancel running status.
+			parser.reset(0)
+			if b < 0xf0 {
+				parser.status = b
+			}
+			parser.msg = []byte{b}
+			parser.need = midiDataLength(b)
+			if parser.need == 0 {
+				msgs = append(msgs, MidiMessage(parser.msg))
+				parser.msg = nil
+			}
+
+		case parser.sysex:
+			parser.msg = append(parser.msg, b)
+
+		default:
+			// Data byte, continuing the message or the running status.
+			if parser.msg == nil {
+				if parser.status == 0 {
+					continue
+				}
+				parser.msg = []byte{parser.status}
+				parser.need = midiDataLength(parser.status)
+			}
+			parser.msg = append(parser.msg, b)
+			parser.need--
+			if parser.need == 0 {
+				msgs = append(msgs, MidiMessage(parser.msg))
+				parser.msg = nil
+			}
+		}
+	}
+
+	return msgs
+}
+
+// reset drops the message being collected and sets running status.
+func (parser *MidiParser) reset(status byte) {
+	parser.status = status
+	parser.msg = nil
+	parser.need = 0
+	parser.sysex = false
+}
+
+// midiDataLength returns number of data bytes following the status byte.
+func midiDataLength(status byte) int {
+	switch status & 0xf0 {
+	case 0xc0, 0xd0:
+		return 1
+	case 0xf0:
+		switch status {
+		case 0xf1, 0xf3:
+			return 1
+		case 0xf2:
+			return 2
+		}
+		return 0
+	}
+
+	return 2
+}
@@ -0,0 +1,52 @@
+package alsa
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMidiParser(t *testing.T) {
+	var parser MidiParser
+
+	// Note on, running status note on split across reads, clock in the
+	// middle, program change and SysEx.
+	msgs := parser.Parse([]byte{0x90, 0x3c, 0x64, 0x3e})
+	msgs = append(msgs, parser.Parse([]byte{0xf8, 0x50, 0xc1, 0x05, 0xf0, 0x7e, 0x7f})...)
+	msgs = append(msgs, parser.Parse([]byte{0x06, 0x01, 0xf7})...)
+
+	expected := []MidiMessage{
+		{0x90, 0x3c, 0x64},
+		{0xf8},
+		{0x90, 0x3e, 0x50},
+		{0xc1, 0x05},
+		{0xf0, 0x7e, 0x7f, 0x06, 0x01, 0xf7},
+	}
+	if len(msgs) != len(expected) {
+		t.Fatalf("Parsed %d messages %x, expected %d", len(msgs), msgs, len(expected))
+	}
+	for i := range expected {
+		if !bytes.Equal(msgs[i], expected[i]) {
+			t.Errorf("Message %d is %x, expected %x", i, msgs[i], expected[i])
+		}
+	}
+
+	if msgs[3].Channel() != 1 || msgs[1].Channel() != -1 || !msgs[4].IsSysEx() {
+		t.Errorf("Unexpected message properties")
+	}
+}
+
+func TestMidiParserSystemCommon(t *testing.T) {
+	var parser MidiParser
+
+	// Song select cancels running status, so the trailing bytes are dropped.
+	msgs := parser.Parse([]byte{0xb0, 0x07, 0x7f, 0xf3, 0x02, 0x07, 0x00})
+	if len(msgs) != 2 || !bytes.Equal(msgs[1], []byte{0xf3, 0x02}) {
+		t.Errorf("Parsed %x", msgs)
+	}
+
+	// Status byte inside SysEx drops it.
+	msgs = parser.Parse([]byte{0xf0, 0x01, 0x80, 0x3c, 0x00})
+	if len(msgs) != 1 || !bytes.Equal(msgs[0], []byte{0x80, 0x3c, 0x00}) {
+		t.Errorf("Parsed %x", msgs)
+	}
+}
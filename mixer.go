@@ -0,0 +1,547 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+import "C"
+
+import (
+	"math"
+	"unsafe"
+)
+
+// Mixer simple element channel.
+type MixerChannel C.snd_mixer_selem_channel_id_t
+
+// Mixer channel constants.
+const (
+	// Unknown
+	MixerChannelUnknown = C.SND_MIXER_SCHN_UNKNOWN
+	// Front left
+	MixerChannelFrontLeft = C.SND_MIXER_SCHN_FRONT_LEFT
+	// Front right
+	MixerChannelFrontRight = C.SND_MIXER_SCHN_FRONT_RIGHT
+	// Rear left
+	MixerChannelRearLeft = C.SND_MIXER_SCHN_REAR_LEFT
+	// Rear right
+	MixerChannelRearRight = C.SND_MIXER_SCHN_REAR_RIGHT
+	// Front center
+	MixerChannelFrontCenter = C.SND_MIXER_SCHN_FRONT_CENTER
+	// Woofer
+	MixerChannelWoofer = C.SND_MIXER_SCHN_WOOFER
+	// Side left
+	MixerChannelSideLeft = C.SND_MIXER_SCHN_SIDE_LEFT
+	// Side right
+	MixerChannelSideRight = C.SND_MIXER_SCHN_SIDE_RIGHT
+	// Rear center
+	MixerChannelRearCenter = C.SND_MIXER_SCHN_REAR_CENTER
+	// Last channel
+	MixerChannelLast = C.SND_MIXER_SCHN_LAST
+	// Mono, same as front left
+	MixerChannelMono = C.SND_MIXER_SCHN_MONO
+)
+
+// String returns ALSA name of the channel, e.g. "Front Left".
+func (channel MixerChannel) String() string {
+	return C.GoString(C.snd_mixer_selem_channel_name(C.snd_mixer_selem_channel_id_t(channel)))
+}
+
+// Mixer is a simple mixer of a sound card, like the one amixer shows.
+type Mixer struct {
+	cMixer *C.snd_mixer_t
+	card   string
+}
+
+// MixerElement is a simple mixer control, e.g. "Master" or "Capture".
+// Playback and capture parts of the element are selected with the stream
+// type argument of its methods.
+type MixerElement struct {
+	mixer *Mixer
+	cElem *C.snd_mixer_elem_t
+}
+
+// OpenMixer opens simple mixer of the card, e.g. "default" or "hw:0".
+func OpenMixer(card string) (*Mixer, error) {
+	mixer := &Mixer{card: card}
+
+	err := C.snd_mixer_open(&mixer.cMixer, 0)
+	if err < 0 {
+		return nil, newDeviceError("open mixer", card, err)
+	}
+
+	cCard := C.CString(card)
+	defer C.free(unsafe.Pointer(cCard))
+
+	err = C.snd_mixer_attach(mixer.cMixer, cCard)
+	if err < 0 {
+		mixer.Close()
+		return nil, mixer.newError("attach mixer", err)
+	}
+
+	err = C.snd_mixer_selem_register(mixer.cMixer, nil, nil)
+	if err < 0 {
+		mixer.Close()
+		return nil, mixer.newError("register simple mixer", err)
+	}
+
+	err = C.snd_mixer_load(mixer.cMixer)
+	if err < 0 {
+		mixer.Close()
+		return nil, mixer.newError("load mixer", err)
+	}
+
+	return mixer, nil
+}
+
+// Close closes the mixer. Its elements must not be used afterwards.
+func (mixer *Mixer) Close() {
+	if mixer.cMixer != nil {
+		C.snd_mixer_close(mixer.cMixer)
+		mixer.cMixer = nil
+	}
+}
+
+// newError returns Error for the failed operation on the mixer card.
+func (mixer *Mixer) newError(op string, code C.int) error {
+	return newDeviceError(op, mixer.card, code)
+}
+
+// Elements returns all simple elements of the mixer.
+func (mixer *Mixer) Elements() []*MixerElement {
+	var elements []*MixerElement
+	for cElem := C.snd_mixer_first_elem(mixer.cMixer); cElem != nil; cElem = C.snd_mixer_elem_next(cElem) {
+		elements = append(elements, &MixerElement{mixer: mixer, cElem: cElem})
+	}
+
+	return elements
+}
+
+// Element returns simple element by its name and index, e.g. "Master", 0.
+// Returns nil when there is no such element.
+func (mixer *Mixer) Element(name string, index int) *MixerElement {
+	var cID *C.snd_mixer_selem_id_t
+	if C.snd_mixer_selem_id_malloc(&cID) < 0 {
+		return nil
+	}
+	defer C.snd_mixer_selem_id_free(cID)
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	C.snd_mixer_selem_id_set_name(cID, cName)
+	C.snd_mixer_selem_id_set_index(cID, C.uint(index))
+
+	cElem := C.snd_mixer_find_selem(mixer.cMixer, cID)
+	if cElem == nil {
+		return nil
+	}
+
+	return &MixerElement{mixer: mixer, cElem: cElem}
+}
+
+// HandleEvents updates elements values after changes made by other
+// clients.
+func (mixer *Mixer) HandleEvents() error {
+	err := C.snd_mixer_handle_events(mixer.cMixer)
+	if err < 0 {
+		return mixer.newError("handle mixer events", err)
+	}
+
+	return nil
+}
+
+// Name returns the element name.
+func (elem *MixerElement) Name() string {
+	return C.GoString(C.snd_mixer_selem_get_name(elem.cElem))
+}
+
+// Index returns the element index, distinguishing elements of one name.
+func (elem *MixerElement) Index() int {
+	return int(C.snd_mixer_selem_get_index(elem.cElem))
+}
+
+// IsActive reports whether the element is active.
+func (elem *MixerElement) IsActive() bool {
+	return C.snd_mixer_selem_is_active(elem.cElem) != 0
+}
+
+// newError returns Error for the failed operation on the element.
+func (elem *MixerElement) newError(op string, code C.int) error {
+	return newDeviceError(op+" "+elem.Name(), elem.mixer.card, code)
+}
+
+// HasVolume reports whether the element has a volume control for the
+// stream direction.
+func (elem *MixerElement) HasVolume(stream StreamType) bool {
+	if stream == StreamTypeCapture {
+		return C.snd_mixer_selem_has_capture_volume(elem.cElem) != 0
+	}
+
+	return C.snd_mixer_selem_has_playback_volume(elem.cElem) != 0
+}
+
+// HasCommonVolume reports whether one volume control serves both playback
+// and capture.
+func (elem *MixerElement) HasCommonVolume() bool {
+	return C.snd_mixer_selem_has_common_volume(elem.cElem) != 0
+}
+
+// IsVolumeJoined reports whether all channels share one volume value.
+func (elem *MixerElement) IsVolumeJoined(stream StreamType) bool {
+	if stream == StreamTypeCapture {
+		return C.snd_mixer_selem_has_capture_volume_joined(elem.cElem) != 0
+	}
+
+	return C.snd_mixer_selem_has_playback_volume_joined(elem.cElem) != 0
+}
+
+// HasSwitch reports whether the element has a switch (mute or capture
+// source) for the stream direction.
+func (elem *MixerElement) HasSwitch(stream StreamType) bool {
+	if stream == StreamTypeCapture {
+		return C.snd_mixer_selem_has_capture_switch(elem.cElem) != 0
+	}
+
+	return C.snd_mixer_selem_has_playback_switch(elem.cElem) != 0
+}
+
+// HasCommonSwitch reports whether one switch serves both playback and
+// capture.
+func (elem *MixerElement) HasCommonSwitch() bool {
+	return C.snd_mixer_selem_has_common_switch(elem.cElem) != 0
+}
+
+// IsSwitchJoined reports whether all channels share one switch value.
+func (elem *MixerElement) IsSwitchJoined(stream StreamType) bool {
+	if stream == StreamTypeCapture {
+		return C.snd_mixer_selem_has_capture_switch_joined(elem.cElem) != 0
+	}
+
+	return C.snd_mixer_selem_has_playback_switch_joined(elem.cElem) != 0
+}
+
+// CaptureGroup returns the group of capture switches of which only one may
+// be on at a time, i.e. capture sources. Returns -1 when the capture switch
+// is not exclusive.
+func (elem *MixerElement) CaptureGroup() int {
+	if C.snd_mixer_selem_has_capture_switch_exclusive(elem.cElem) == 0 {
+		return -1
+	}
+
+	return int(C.snd_mixer_selem_get_capture_group(elem.cElem))
+}
+
+// IsMono reports whether the element has a single channel for the stream
+// direction.
+func (elem *MixerElement) IsMono(stream StreamType) bool {
+	if stream == StreamTypeCapture {
+		return C.snd_mixer_selem_is_capture_mono(elem.cElem) != 0
+	}
+
+	return C.snd_mixer_selem_is_playback_mono(elem.cElem) != 0
+}
+
+// HasChannel reports whether the element has the channel for the stream
+// direction.
+func (elem *MixerElement) HasChannel(stream StreamType, channel MixerChannel) bool {
+	cChannel := C.snd_mixer_selem_channel_id_t(channel)
+	if stream == StreamTypeCapture {
+		return C.snd_mixer_selem_has_capture_channel(elem.cElem, cChannel) != 0
+	}
+
+	return C.snd_mixer_selem_has_playback_channel(elem.cElem, cChannel) != 0
+}
+
+// Channels returns channels of the element for the stream direction.
+func (elem *MixerElement) Channels(stream StreamType) []MixerChannel {
+	var channels []MixerChannel
+	for channel := MixerChannel(0); channel <= MixerChannelLast; channel++ {
+		if elem.HasChannel(stream, channel) {
+			channels = append(channels, channel)
+		}
+	}
+
+	return channels
+}
+
+// VolumeRange returns the raw volume range.
+func (elem *MixerElement) VolumeRange(stream StreamType) (min, max int, err error) {
+	var cMin, cMax C.long
+	var cErr C.int
+	if stream == StreamTypeCapture {
+		cErr = C.snd_mixer_selem_get_capture_volume_range(elem.cElem, &cMin, &cMax)
+	} else {
+		cErr = C.snd_mixer_selem_get_playback_volume_range(elem.cElem, &cMin, &cMax)
+	}
+	if cErr < 0 {
+		return 0, 0, elem.newError("get volume range", cErr)
+	}
+
+	return int(cMin), int(cMax), nil
+}
+
+// Volume returns the raw volume of the channel.
+func (elem *MixerElement) Volume(stream StreamType, channel MixerChannel) (int, error) {
+	var value C.long
+	var err C.int
+	cChannel := C.snd_mixer_selem_channel_id_t(channel)
+	if stream == StreamTypeCapture {
+		err = C.snd_mixer_selem_get_capture_volume(elem.cElem, cChannel, &value)
+	} else {
+		err = C.snd_mixer_selem_get_playback_volume(elem.cElem, cChannel, &value)
+	}
+	if err < 0 {
+		return 0, elem.newError("get volume", err)
+	}
+
+	return int(value), nil
+}
+
+// SetVolume sets the raw volume of the channel.
+func (elem *MixerElement) SetVolume(stream StreamType, channel MixerChannel, value int) error {
+	var err C.int
+	cChannel := C.snd_mixer_selem_channel_id_t(channel)
+	if stream == StreamTypeCapture {
+		err = C.snd_mixer_selem_set_capture_volume(elem.cElem, cChannel, C.long(value))
+	} else {
+		err = C.snd_mixer_selem_set_playback_volume(elem.cElem, cChannel, C.long(value))
+	}
+	if err < 0 {
+		return elem.newError("set volume", err)
+	}
+
+	return nil
+}
+
+// SetVolumeAll sets the raw volume of all channels.
+func (elem *MixerElement) SetVolumeAll(stream StreamType, value int) error {
+	var err C.int
+	if stream == StreamTypeCapture {
+		err = C.snd_mixer_selem_set_capture_volume_all(elem.cElem, C.long(value))
+	} else {
+		err = C.snd_mixer_selem_set_playback_volume_all(elem.cElem, C.long(value))
+	}
+	if err < 0 {
+		return elem.newError("set volume", err)
+	}
+
+	return nil
+}
+
+// DBRange returns the volume range in dB. Minimum is -Inf when the lowest
+// volume mutes.
+func (elem *MixerElement) DBRange(stream StreamType) (min, max float64, err error) {
+	var cMin, cMax C.long
+	var cErr C.int
+	if stream == StreamTypeCapture {
+		cErr = C.snd_mixer_selem_get_capture_dB_range(elem.cElem, &cMin, &cMax)
+	} else {
+		cErr = C.snd_mixer_selem_get_playback_dB_range(elem.cElem, &cMin, &cMax)
+	}
+	if cErr < 0 {
+		return 0, 0, elem.newError("get dB range", cErr)
+	}
+
+	return fromCentiDB(cMin), fromCentiDB(cMax), nil
+}
+
+// DB returns the volume of the channel in dB.
+func (elem *MixerElement) DB(stream StreamType, channel MixerChannel) (float64, error) {
+	var value C.long
+	var err C.int
+	cChannel := C.snd_mixer_selem_channel_id_t(channel)
+	if stream == StreamTypeCapture {
+		err = C.snd_mixer_selem_get_capture_dB(elem.cElem, cChannel, &value)
+	} else {
+		err = C.snd_mixer_selem_get_playback_dB(elem.cElem, cChannel, &value)
+	}
+	if err < 0 {
+		return 0, elem.newError("get dB", err)
+	}
+
+	return fromCentiDB(value), nil
+}
+
+// SetDB sets the volume of the channel in dB. When the value falls between
+// volume steps it is rounded up for positive dir and down otherwise.
+func (elem *MixerElement) SetDB(stream StreamType, channel MixerChannel, dB float64, dir int) error {
+	var err C.int
+	cChannel := C.snd_mixer_selem_channel_id_t(channel)
+	if stream == StreamTypeCapture {
+		err = C.snd_mixer_selem_set_capture_dB(elem.cElem, cChannel, toCentiDB(dB), C.int(dir))
+	} else {
+		err = C.snd_mixer_selem_set_playback_dB(elem.cElem, cChannel, toCentiDB(dB), C.int(dir))
+	}
+	if err < 0 {
+		return elem.newError("set dB", err)
+	}
+
+	return nil
+}
+
+// SetDBAll sets the volume of all channels in dB, rounded like SetDB.
+func (elem *MixerElement) SetDBAll(stream StreamType, dB float64, dir int) error {
+	var err C.int
+	if stream == StreamTypeCapture {
+		err = C.snd_mixer_selem_set_capture_dB_all(elem.cElem, toCentiDB(dB), C.int(dir))
+	} else {
+		err = C.snd_mixer_selem_set_playback_dB_all(elem.cElem, toCentiDB(dB), C.int(dir))
+	}
+	if err < 0 {
+		return elem.newError("set dB", err)
+	}
+
+	return nil
+}
+
+// VolumeToDB converts the raw volume to dB.
+func (elem *MixerElement) VolumeToDB(stream StreamType, value int) (float64, error) {
+	var dB C.long
+	var err C.int
+	if stream == StreamTypeCapture {
+		err = C.snd_mixer_selem_ask_capture_vol_dB(elem.cElem, C.long(value), &dB)
+	} else {
+		err = C.snd_mixer_selem_ask_playback_vol_dB(elem.cElem, C.long(value), &dB)
+	}
+	if err < 0 {
+		return 0, elem.newError("convert volume to dB", err)
+	}
+
+	return fromCentiDB(dB), nil
+}
+
+// DBToVolume converts dB to the raw volume, rounded like SetDB.
+func (elem *MixerElement) DBToVolume(stream StreamType, dB float64, dir int) (int, error) {
+	var value C.long
+	var err C.int
+	if stream == StreamTypeCapture {
+		err = C.snd_mixer_selem_ask_capture_dB_vol(elem.cElem, toCentiDB(dB), C.int(dir), &value)
+	} else {
+		err = C.snd_mixer_selem_ask_playback_dB_vol(elem.cElem, toCentiDB(dB), C.int(dir), &value)
+	}
+	if err < 0 {
+		return 0, elem.newError("convert dB to volume", err)
+	}
+
+	return int(value), nil
+}
+
+// Switch returns the switch of the channel. Playback switch off means
+// muted, capture switch on means the element is a capture source.
+func (elem *MixerElement) Switch(stream StreamType, channel MixerChannel) (bool, error) {
+	var value C.int
+	var err C.int
+	cChannel := C.snd_mixer_selem_channel_id_t(channel)
+	if stream == StreamTypeCapture {
+		err = C.snd_mixer_selem_get_capture_switch(elem.cElem, cChannel, &value)
+	} else {
+		err = C.snd_mixer_selem_get_playback_switch(elem.cElem, cChannel, &value)
+	}
+	if err < 0 {
+		return false, elem.newError("get switch", err)
+	}
+
+	return value != 0, nil
+}
+
+// SetSwitch sets the switch of the channel.
+func (elem *MixerElement) SetSwitch(stream StreamType, channel MixerChannel, on bool) error {
+	var err C.int
+	cChannel := C.snd_mixer_selem_channel_id_t(channel)
+	if stream == StreamTypeCapture {
+		err = C.snd_mixer_selem_set_capture_switch(elem.cElem, cChannel, cBool(on))
+	} else {
+		err = C.snd_mixer_selem_set_playback_switch(elem.cElem, cChannel, cBool(on))
+	}
+	if err < 0 {
+		return elem.newError("set switch", err)
+	}
+
+	return nil
+}
+
+// SetSwitchAll sets the switch of all channels.
+func (elem *MixerElement) SetSwitchAll(stream StreamType, on bool) error {
+	var err C.int
+	if stream == StreamTypeCapture {
+		err = C.snd_mixer_selem_set_capture_switch_all(elem.cElem, cBool(on))
+	} else {
+		err = C.snd_mixer_selem_set_playback_switch_all(elem.cElem, cBool(on))
+	}
+	if err < 0 {
+		return elem.newError("set switch", err)
+	}
+
+	return nil
+}
+
+// IsEnumerated reports whether the element selects one of named items,
+// e.g. "Capture Source". Use IsEnumCapture to tell capture enumerations.
+func (elem *MixerElement) IsEnumerated() bool {
+	return C.snd_mixer_selem_is_enumerated(elem.cElem) != 0
+}
+
+// IsEnumCapture reports whether the enumerated element belongs to capture.
+func (elem *MixerElement) IsEnumCapture() bool {
+	return C.snd_mixer_selem_is_enum_capture(elem.cElem) != 0
+}
+
+// EnumItems returns item names of the enumerated element.
+func (elem *MixerElement) EnumItems() ([]string, error) {
+	count := C.snd_mixer_selem_get_enum_items(elem.cElem)
+	if count < 0 {
+		return nil, elem.newError("get enum items", count)
+	}
+
+	var buf [256]C.char
+	items := make([]string, count)
+	for i := range items {
+		err := C.snd_mixer_selem_get_enum_item_name(elem.cElem, C.uint(i), C.size_t(len(buf)), &buf[0])
+		if err < 0 {
+			return nil, elem.newError("get enum item name", err)
+		}
+		items[i] = C.GoString(&buf[0])
+	}
+
+	return items, nil
+}
+
+// EnumItem returns index of the item selected on the channel.
+func (elem *MixerElement) EnumItem(channel MixerChannel) (int, error) {
+	var index C.uint
+	err := C.snd_mixer_selem_get_enum_item(elem.cElem, C.snd_mixer_selem_channel_id_t(channel), &index)
+	if err < 0 {
+		return 0, elem.newError("get enum item", err)
+	}
+
+	return int(index), nil
+}
+
+// SetEnumItem selects the item by index on the channel.
+func (elem *MixerElement) SetEnumItem(channel MixerChannel, index int) error {
+	err := C.snd_mixer_selem_set_enum_item(elem.cElem, C.snd_mixer_selem_channel_id_t(channel), C.uint(index))
+	if err < 0 {
+		return elem.newError("set enum item", err)
+	}
+
+	return nil
+}
+
+// mixerMuteDB is ALSA value of mute in 0.01 dB units (SND_CTL_TLV_DB_GAIN_MUTE).
+const mixerMuteDB = -9999999
+
+// fromCentiDB converts ALSA 0.01 dB units to dB.
+func fromCentiDB(value C.long) float64 {
+	if value <= mixerMuteDB {
+		return math.Inf(-1)
+	}
+
+	return float64(value) / 100
+}
+
+// toCentiDB converts dB to ALSA 0.01 dB units.
+func toCentiDB(dB float64) C.long {
+	if math.IsInf(dB, -1) {
+		return mixerMuteDB
+	}
+
+	return C.long(math.Round(dB * 100))
+}
@@ -0,0 +1,92 @@
+package alsa
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMixerVolume(t *testing.T) {
+	mixer, err := OpenMixer("default")
+	if err != nil {
+		t.Fatalf("OpenMixer failed. %s", err)
+	}
+	defer mixer.Close()
+
+	var elem *MixerElement
+	for _, e := range mixer.Elements() {
+		if e.HasVolume(StreamTypePlayback) && len(e.Channels(StreamTypePlayback)) > 0 {
+			elem = e
+			break
+		}
+	}
+	if elem == nil {
+		t.Skip("No playback volume element")
+	}
+
+	if found := mixer.Element(elem.Name(), elem.Index()); found == nil || found.Name() != elem.Name() {
+		t.Errorf("Element %s not found by name", elem.Name())
+	}
+
+	channel := elem.Channels(StreamTypePlayback)[0]
+	min, max, err := elem.VolumeRange(StreamTypePlayback)
+	if err != nil {
+		t.Fatalf("VolumeRange failed. %s", err)
+	}
+
+	volume, err := elem.Volume(StreamTypePlayback, channel)
+	if err != nil {
+		t.Fatalf("Volume failed. %s", err)
+	}
+	defer elem.SetVolume(StreamTypePlayback, channel, volume)
+
+	if volume < min || volume > max {
+		t.Errorf("Volume %d out of range %d-%d", volume, min, max)
+	}
+
+	err = elem.SetVolume(StreamTypePlayback, channel, min)
+	if err != nil {
+		t.Fatalf("SetVolume failed. %s", err)
+	}
+	if got, _ := elem.Volume(StreamTypePlayback, channel); got != min {
+		t.Errorf("Volume %d after setting %d", got, min)
+	}
+
+	minDB, maxDB, err := elem.DBRange(StreamTypePlayback)
+	if err != nil {
+		t.Skipf("No dB information. %s", err)
+	}
+	if minDB > maxDB {
+		t.Errorf("Invalid dB range %v-%v", minDB, maxDB)
+	}
+
+	dB, err := elem.VolumeToDB(StreamTypePlayback, max)
+	if err != nil {
+		t.Fatalf("VolumeToDB failed. %s", err)
+	}
+	if dB != maxDB {
+		t.Errorf("Max volume is %v dB, expected %v dB", dB, maxDB)
+	}
+
+	raw, err := elem.DBToVolume(StreamTypePlayback, maxDB, 0)
+	if err != nil {
+		t.Fatalf("DBToVolume failed. %s", err)
+	}
+	if raw != max {
+		t.Errorf("Max dB is volume %d, expected %d", raw, max)
+	}
+}
+
+func TestCentiDB(t *testing.T) {
+	if dB := fromCentiDB(-1250); dB != -12.5 {
+		t.Errorf("Converted -1250 to %v dB", dB)
+	}
+	if dB := fromCentiDB(mixerMuteDB); !math.IsInf(dB, -1) {
+		t.Errorf("Converted mute to %v dB", dB)
+	}
+	if value := toCentiDB(-12.5); value != -1250 {
+		t.Errorf("Converted -12.5 dB to %d", value)
+	}
+	if value := toCentiDB(math.Inf(-1)); value != mixerMuteDB {
+		t.Errorf("Converted -Inf dB to %d", value)
+	}
+}
@@ -0,0 +1,287 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+import "C"
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+)
+
+// MmapArea is a view of a contiguous region of the device ring buffer.
+// It is valid until Commit. Stream must use AccessMmapInterleaved or
+// AccessMmapNonInterleaved access.
+type MmapArea struct {
+	handle *Handle
+	offset C.snd_pcm_uframes_t
+	// Number of frames in the region.
+	Frames int
+	// First sample address and distance between samples in bytes per channel.
+	bases []unsafe.Pointer
+	steps []int
+}
+
+// MmapBegin returns a view of at most frames frames of the ring buffer
+// which are ready to be written (playback) or read (capture). The region
+// stops at the end of the ring, so it may be shorter than requested;
+// zero frames means the buffer is full (playback) or empty (capture).
+func (handle *Handle) MmapBegin(frames int) (*MmapArea, error) {
+	if handle.FrameSize() == 0 {
+		return nil, ErrNotConfigured
+	}
+
+	// Pointers must be synchronized before snd_pcm_mmap_begin.
+	avail := C.snd_pcm_avail_update(handle.cHandle)
+	if avail < 0 {
+		return nil, handle.newError("get available frames", C.int(avail))
+	}
+
+	var cAreas *C.snd_pcm_channel_area_t
+	var offset C.snd_pcm_uframes_t
+	cFrames := C.snd_pcm_uframes_t(frames)
+
+	err := C.snd_pcm_mmap_begin(handle.cHandle, &cAreas, &offset, &cFrames)
+	if err < 0 {
+		return nil, handle.newError("begin mmap access", err)
+	}
+
+	area := &MmapArea{
+		handle: handle,
+		offset: offset,
+		Frames: int(cFrames),
+		bases:  make([]unsafe.Pointer, handle.Channels),
+		steps:  make([]int, handle.Channels),
+	}
+
+	for i, cArea := range unsafe.Slice(cAreas, handle.Channels) {
+		// first and step are in bits.
+		area.steps[i] = int(cArea.step) / 8
+		area.bases[i] = unsafe.Add(cArea.addr, int(cArea.first)/8+int(offset)*area.steps[i])
+	}
+
+	return area, nil
+}
+
+// Channel returns samples of the channel in the region and distance between
+// two consecutive samples in bytes. For interleaved access samples of other
+// channels lie between them.
+func (area *MmapArea) Channel(channel int) (buf []byte, step int) {
+	if area.Frames == 0 {
+		return nil, area.steps[channel]
+	}
+
+	size := (area.Frames-1)*area.steps[channel] + area.handle.SampleSize()
+
+	return unsafe.Slice((*byte)(area.bases[channel]), size), area.steps[channel]
+}
+
+// Sample returns bytes of one sample of the channel at the frame of the region.
+func (area *MmapArea) Sample(channel int, frame int) []byte {
+	if frame < 0 || frame >= area.Frames {
+		panic("alsa: mmap frame out of range")
+	}
+
+	sample := unsafe.Add(area.bases[channel], frame*area.steps[channel])
+
+	return unsafe.Slice((*byte)(sample), area.handle.SampleSize())
+}
+
+// Interleaved returns the region as one slice of interleaved frames, or nil
+// when the channels are not laid out as contiguous interleaved frames.
+func (area *MmapArea) Interleaved() []byte {
+	sampleSize := area.handle.SampleSize()
+	frameSize := area.handle.FrameSize()
+
+	for i := range area.bases {
+		if area.steps[i] != frameSize || area.bases[i] != unsafe.Add(area.bases[0], i*sampleSize) {
+			return nil
+		}
+	}
+
+	return unsafe.Slice((*byte)(area.bases[0]), area.Frames*frameSize)
+}
+
+// Commit hands first frames of the region over to the device (playback)
+// or releases them (capture). The area must not be used afterwards.
+func (area *MmapArea) Commit(frames int) (int, error) {
+	handle := area.handle
+
+	committed := C.snd_pcm_mmap_commit(handle.cHandle, area.offset, C.snd_pcm_uframes_t(frames))
+	if committed < 0 {
+		return 0, handle.newError("commit mmap access", C.int(committed))
+	}
+	if int(committed) != frames {
+		return int(committed), handle.newError("commit mmap access", -C.EPIPE)
+	}
+
+	return int(committed), nil
+}
+
+// MmapWrite copies interleaved PCM data directly into the ring buffer,
+// splitting it at the end of the ring. Blocks in Wait while the buffer is
+// full. Starts the prepared stream when it fills up or queued frames reach
+// the start threshold.
+// Returns wrote value is total bytes was written.
+func (handle *Handle) MmapWrite(buf []byte) (wrote int, err error) {
+	return handle.mmapTransfer(buf, "write", copyToArea)
+}
+
+// MmapRead copies captured PCM data directly from the ring buffer into buf
+// as interleaved frames. Blocks in Wait while the buffer is empty.
+// Returns read value in number of bytes read.
+func (handle *Handle) MmapRead(buf []byte) (read int, err error) {
+	return handle.mmapTransfer(buf, "read", copyFromArea)
+}
+
+// mmapTransfer moves whole frames of buf through mmap areas with transfer.
+func (handle *Handle) mmapTransfer(buf []byte, op string, transfer func(*MmapArea, []byte)) (int, error) {
+	if handle.FrameSize() == 0 {
+		return 0, ErrNotConfigured
+	}
+
+	frameSize := handle.FrameSize()
+	frames := len(buf) / frameSize
+	done := 0
+	recoveries := 0
+
+	for done < frames {
+		area, err := handle.MmapBegin(frames - done)
+		if err == nil && area.Frames == 0 {
+			err = handle.mmapWait(op)
+			if err == nil {
+				continue
+			}
+		}
+
+		if err == nil {
+			transfer(area, buf[done*frameSize:(done+area.Frames)*frameSize])
+
+			var committed int
+			committed, err = area.Commit(area.Frames)
+			done += committed
+			if committed > 0 {
+				recoveries = 0
+			}
+			if err == nil {
+				continue
+			}
+		}
+
+		var alsaErr *Error
+		if !errors.As(err, &alsaErr) ||
+			(alsaErr.Errno != syscall.EPIPE && alsaErr.Errno != syscall.ESTRPIPE) {
+			return done * frameSize, err
+		}
+
+		recoveries++
+		if recoveries > maxRecoveries {
+			return done * frameSize, err
+		}
+
+		lost, err := handle.recoverXrun(op, -C.int(alsaErr.Errno))
+		if err != nil {
+			return done * frameSize, err
+		}
+
+		if handle.Recovery == RecoveryInsertSilence && handle.streamType == StreamTypeCapture {
+			if lost > frames-done {
+				lost = frames - done
+			}
+			handle.fillSilence(buf[done*frameSize : (done+lost)*frameSize])
+			done += lost
+			if lost > 0 {
+				recoveries = 0
+			}
+		}
+	}
+
+	if handle.streamType == StreamTypePlayback && done > 0 {
+		if err := handle.mmapStart(); err != nil {
+			return done * frameSize, err
+		}
+	}
+
+	return done * frameSize, nil
+}
+
+// mmapStart starts prepared playback stream once queued frames reach the
+// start threshold, as snd_pcm_mmap_writei does.
+func (handle *Handle) mmapStart() error {
+	if handle.State() != PCMStatePrepared {
+		return nil
+	}
+
+	params, err := handle.SwParams()
+	if err != nil {
+		return err
+	}
+	defer params.Free()
+
+	threshold, err := params.StartThreshold()
+	if err != nil {
+		return err
+	}
+
+	avail := C.snd_pcm_avail_update(handle.cHandle)
+	if avail < 0 {
+		return handle.newError("get available frames", C.int(avail))
+	}
+
+	if handle.Buffersize-int(avail) < threshold {
+		return nil
+	}
+
+	return handle.Start()
+}
+
+// mmapWait starts prepared stream and waits till the ring buffer is ready.
+func (handle *Handle) mmapWait(op string) error {
+	if handle.State() == PCMStatePrepared {
+		if err := handle.Start(); err != nil {
+			return err
+		}
+	}
+
+	if handle.mode&ModeNonblock != 0 {
+		return handle.newError(op, -C.EAGAIN)
+	}
+
+	_, err := handle.Wait(-1)
+
+	return err
+}
+
+// copyToArea copies interleaved frames from data into the area.
+func copyToArea(area *MmapArea, data []byte) {
+	if dst := area.Interleaved(); dst != nil {
+		copy(dst, data)
+		return
+	}
+
+	sampleSize := area.handle.SampleSize()
+	frameSize := area.handle.FrameSize()
+	for frame := 0; frame < area.Frames; frame++ {
+		for channel := range area.bases {
+			src := data[frame*frameSize+channel*sampleSize:]
+			copy(area.Sample(channel, frame), src[:sampleSize])
+		}
+	}
+}
+
+// copyFromArea copies frames of the area into data as interleaved frames.
+func copyFromArea(area *MmapArea, data []byte) {
+	if src := area.Interleaved(); src != nil {
+		copy(data, src)
+		return
+	}
+
+	sampleSize := area.handle.SampleSize()
+	frameSize := area.handle.FrameSize()
+	for frame := 0; frame < area.Frames; frame++ {
+		for channel := range area.bases {
+			dst := data[frame*frameSize+channel*sampleSize:]
+			copy(dst[:sampleSize], area.Sample(channel, frame))
+		}
+	}
+}
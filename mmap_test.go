@@ -0,0 +1,74 @@
+package alsa
+
+import (
+	"testing"
+)
+
+func TestMmapWrite(t *testing.T) {
+	handle := New()
+	err := handle.Open("default", StreamTypePlayback, ModeBlock)
+	if err != nil {
+		t.Fatalf("Open failed. %s", err)
+	}
+	defer handle.Close()
+
+	handle.SampleFormat = SampleFormatS16LE
+	handle.SampleRate = 44100
+	handle.Channels = 2
+	handle.Access = AccessMmapInterleaved
+	err = handle.ApplyHwParams()
+	if err != nil {
+		t.Fatalf("ApplyHwParams failed. %s", err)
+	}
+
+	// More than the ring holds, so the write wraps around at least once.
+	buf := make([]byte, (handle.Buffersize+handle.PeriodSize)*handle.FrameSize())
+
+	wrote, err := handle.MmapWrite(buf)
+	if err != nil {
+		t.Fatalf("MmapWrite failed. %s", err)
+	}
+	if wrote != len(buf) {
+		t.Errorf("Did not write all the buffer (Wrote %d, expected %d)", wrote, len(buf))
+	}
+}
+
+func TestMmapWriteStartThreshold(t *testing.T) {
+	handle := New()
+	err := handle.Open("default", StreamTypePlayback, ModeBlock)
+	if err != nil {
+		t.Fatalf("Open failed. %s", err)
+	}
+	defer handle.Close()
+
+	handle.SampleFormat = SampleFormatS16LE
+	handle.SampleRate = 44100
+	handle.Channels = 2
+	handle.Access = AccessMmapInterleaved
+	err = handle.ApplyHwParams()
+	if err != nil {
+		t.Fatalf("ApplyHwParams failed. %s", err)
+	}
+
+	params, err := handle.SwParams()
+	if err != nil {
+		t.Fatalf("SwParams failed. %s", err)
+	}
+	defer params.Free()
+	if err = params.SetStartThreshold(handle.PeriodSize); err != nil {
+		t.Fatalf("SetStartThreshold failed. %s", err)
+	}
+	if err = params.Apply(); err != nil {
+		t.Fatalf("Apply failed. %s", err)
+	}
+
+	// One period is less than the ring holds, but reaches the threshold.
+	buf := make([]byte, handle.PeriodSize*handle.FrameSize())
+	if _, err = handle.MmapWrite(buf); err != nil {
+		t.Fatalf("MmapWrite failed. %s", err)
+	}
+
+	if state := handle.State(); state != PCMStateRunning {
+		t.Errorf("State after MmapWrite is %s", state)
+	}
+}
@@ -0,0 +1,215 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+import "C"
+
+import (
+	"errors"
+	"syscall"
+	"time"
+)
+
+// ErrWoken is returned by Poller.Wait interrupted with Poller.Wake.
+var ErrWoken = errors.New("alsa: poller woken")
+
+// Poll event flags.
+type PollEvents int16
+
+// Poll event constants.
+const (
+	// Data may be read
+	PollIn = C.POLLIN
+	// Data may be written
+	PollOut = C.POLLOUT
+	// Error condition, e.g. xrun
+	PollErr = C.POLLERR
+	// Hang up, e.g. device disconnected
+	PollHup = C.POLLHUP
+	// Invalid descriptor
+	PollNval = C.POLLNVAL
+)
+
+// PollDescriptor is a file descriptor to poll for the stream readiness.
+type PollDescriptor struct {
+	Fd      int
+	Events  PollEvents
+	Revents PollEvents
+}
+
+// PollDescriptors returns descriptors to poll for the stream readiness.
+// Returned events must be demangled with PollRevents.
+func (handle *Handle) PollDescriptors() ([]PollDescriptor, error) {
+	cFds, err := handle.pollFds()
+	if err != nil {
+		return nil, err
+	}
+
+	fds := make([]PollDescriptor, len(cFds))
+	for i, cFd := range cFds {
+		fds[i] = PollDescriptor{Fd: int(cFd.fd), Events: PollEvents(cFd.events)}
+	}
+
+	return fds, nil
+}
+
+// PollRevents returns stream events from revents of the polled descriptors.
+// PollIn or PollOut means the stream is ready for I/O, PollErr reports xrun.
+func (handle *Handle) PollRevents(fds []PollDescriptor) (PollEvents, error) {
+	if len(fds) == 0 {
+		return 0, nil
+	}
+
+	cFds := make([]C.struct_pollfd, len(fds))
+	for i, fd := range fds {
+		cFds[i] = C.struct_pollfd{fd: C.int(fd.Fd), events: C.short(fd.Events), revents: C.short(fd.Revents)}
+	}
+
+	return handle.pollRevents(cFds)
+}
+
+// pollFds returns poll descriptors of the stream.
+func (handle *Handle) pollFds() ([]C.struct_pollfd, error) {
+	count := C.snd_pcm_poll_descriptors_count(handle.cHandle)
+	if count < 0 {
+		return nil, handle.newError("get poll descriptors count", count)
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	cFds := make([]C.struct_pollfd, count)
+	filled := C.snd_pcm_poll_descriptors(handle.cHandle, &cFds[0], C.uint(count))
+	if filled < 0 {
+		return nil, handle.newError("get poll descriptors", filled)
+	}
+
+	return cFds[:filled], nil
+}
+
+// pollRevents demangles revents of the stream poll descriptors.
+func (handle *Handle) pollRevents(cFds []C.struct_pollfd) (PollEvents, error) {
+	var revents C.ushort
+	err := C.snd_pcm_poll_descriptors_revents(handle.cHandle, &cFds[0], C.uint(len(cFds)), &revents)
+	if err < 0 {
+		return 0, handle.newError("get poll events", err)
+	}
+
+	return PollEvents(revents), nil
+}
+
+// Poller waits for readiness of several streams in one goroutine.
+// Wait may be interrupted from other goroutines with Wake.
+type Poller struct {
+	// Wake pipe read and write ends.
+	wakeFds [2]int
+}
+
+// NewPoller returns new poller. It must be released with Close.
+func NewPoller() (*Poller, error) {
+	poller := new(Poller)
+
+	err := syscall.Pipe2(poller.wakeFds[:], syscall.O_NONBLOCK|syscall.O_CLOEXEC)
+	if err != nil {
+		return nil, err
+	}
+
+	return poller, nil
+}
+
+// Close releases the poller wake pipe.
+func (poller *Poller) Close() {
+	syscall.Close(poller.wakeFds[0])
+	syscall.Close(poller.wakeFds[1])
+}
+
+// Wake interrupts current or next Wait call, which returns ErrWoken.
+func (poller *Poller) Wake() {
+	// Pipe full means a wakeup is already pending.
+	syscall.Write(poller.wakeFds[1], []byte{0})
+}
+
+// Wait waits till some of the streams are ready for I/O, or report an
+// error like xrun, and returns them. Negative timeout waits forever; on
+// timeout empty slice is returned.
+func (poller *Poller) Wait(handles []*Handle, timeout time.Duration) ([]*Handle, error) {
+	var pfds []C.struct_pollfd
+	offsets := make([]int, len(handles)+1)
+
+	for i, handle := range handles {
+		offsets[i] = len(pfds)
+		cFds, err := handle.pollFds()
+		if err != nil {
+			return nil, err
+		}
+		pfds = append(pfds, cFds...)
+	}
+	offsets[len(handles)] = len(pfds)
+
+	err := poller.poll(pfds, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var ready []*Handle
+	for i, handle := range handles {
+		cFds := pfds[offsets[i]:offsets[i+1]]
+		if len(cFds) == 0 {
+			continue
+		}
+
+		revents, err := handle.pollRevents(cFds)
+		if err != nil {
+			return ready, err
+		}
+		if revents&(PollIn|PollOut|PollErr|PollHup) != 0 {
+			ready = append(ready, handle)
+		}
+	}
+
+	return ready, nil
+}
+
+// poll waits for events on the descriptors or the wake pipe, and stores
+// returned events in pfds.
+func (poller *Poller) poll(pfds []C.struct_pollfd, timeout time.Duration) error {
+	all := append([]C.struct_pollfd{{fd: C.int(poller.wakeFds[0]), events: C.POLLIN}}, pfds...)
+
+	deadline := time.Now().Add(timeout)
+	for {
+		ms := -1
+		if timeout >= 0 {
+			ms = int(time.Until(deadline).Round(time.Millisecond) / time.Millisecond)
+			if ms < 0 {
+				ms = 0
+			}
+		}
+
+		n, err := C.poll(&all[0], C.nfds_t(len(all)), C.int(ms))
+		if n < 0 {
+			if err == syscall.EINTR {
+				continue
+			}
+			return err
+		}
+		break
+	}
+
+	if all[0].revents != 0 {
+		poller.drain()
+		return ErrWoken
+	}
+	copy(pfds, all[1:])
+
+	return nil
+}
+
+// drain consumes pending wakeups.
+func (poller *Poller) drain() {
+	buf := make([]byte, 64)
+	for {
+		n, err := syscall.Read(poller.wakeFds[0], buf)
+		if n <= 0 || err != nil {
+			return
+		}
+	}
+}
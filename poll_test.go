@@ -0,0 +1,69 @@
+package alsa
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPollerWake(t *testing.T) {
+	poller, err := NewPoller()
+	if err != nil {
+		t.Fatalf("NewPoller failed. %s", err)
+	}
+	defer poller.Close()
+
+	ready, err := poller.Wait(nil, 10*time.Millisecond)
+	if err != nil || len(ready) != 0 {
+		t.Fatalf("Wait timeout returned %v, %v", ready, err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		poller.Wake()
+	}()
+
+	_, err = poller.Wait(nil, -1)
+	if err != ErrWoken {
+		t.Fatalf("Wait returned %v, expected ErrWoken", err)
+	}
+}
+
+func TestPollerReady(t *testing.T) {
+	handle := New()
+	err := handle.Open("default", StreamTypePlayback, ModeNonblock)
+	if err != nil {
+		t.Fatalf("Open failed. %s", err)
+	}
+	defer handle.Close()
+
+	handle.SampleFormat = SampleFormatS16LE
+	handle.SampleRate = 44100
+	handle.Channels = 2
+	err = handle.ApplyHwParams()
+	if err != nil {
+		t.Fatalf("ApplyHwParams failed. %s", err)
+	}
+
+	fds, err := handle.PollDescriptors()
+	if err != nil {
+		t.Fatalf("PollDescriptors failed. %s", err)
+	}
+	if len(fds) == 0 {
+		t.Fatalf("No poll descriptors")
+	}
+
+	poller, err := NewPoller()
+	if err != nil {
+		t.Fatalf("NewPoller failed. %s", err)
+	}
+	defer poller.Close()
+
+	// Empty playback buffer is ready for writing.
+	ready, err := poller.Wait([]*Handle{handle}, time.Second)
+	if err != nil {
+		t.Fatalf("Wait failed. %s", err)
+	}
+	if len(ready) != 1 || ready[0] != handle {
+		t.Errorf("Handle is not ready for writing")
+	}
+}
@@ -0,0 +1,369 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Raw MIDI stream direction.
+type RawMidiStream C.snd_rawmidi_stream_t
+
+// Raw MIDI stream constants.
+const (
+	// Output stream
+	RawMidiStreamOutput = C.SND_RAWMIDI_STREAM_OUTPUT
+	// Input stream
+	RawMidiStreamInput = C.SND_RAWMIDI_STREAM_INPUT
+)
+
+// Raw MIDI open mode flags.
+const (
+	// Append output to the device, shared by several clients
+	RawMidiModeAppend = C.SND_RAWMIDI_APPEND
+	// Nonblocking read and write
+	RawMidiModeNonblock = C.SND_RAWMIDI_NONBLOCK
+	// Write returns after the data is sent
+	RawMidiModeSync = C.SND_RAWMIDI_SYNC
+)
+
+// RawMidi is a raw MIDI device opened for input, output or both.
+type RawMidi struct {
+	cInput  *C.snd_rawmidi_t
+	cOutput *C.snd_rawmidi_t
+	name    string
+}
+
+// RawMidiParams are buffer parameters of a raw MIDI stream.
+type RawMidiParams struct {
+	// Buffer size in bytes.
+	BufferSize int
+	// Minimum number of bytes to consider the stream ready.
+	AvailMin int
+	// Do not send active sensing on output close.
+	NoActiveSensing bool
+}
+
+// RawMidiDevice describes a raw MIDI subdevice of a card.
+type RawMidiDevice struct {
+	// Card index.
+	Card int
+	// Device index on the card.
+	Device int
+	// Subdevice index.
+	Subdevice int
+	// Device identifier.
+	ID string
+	// Device name.
+	Name string
+	// Subdevice name.
+	SubdeviceName string
+	// Subdevice has an input stream.
+	Input bool
+	// Subdevice has an output stream.
+	Output bool
+}
+
+// HwName returns the device name to pass to OpenRawMidi, e.g. "hw:1,0,0".
+func (device RawMidiDevice) HwName() string {
+	return fmt.Sprintf("hw:%d,%d,%d", device.Card, device.Device, device.Subdevice)
+}
+
+// OpenRawMidi opens raw MIDI device, e.g. "hw:1,0", for input, output or
+// both. Mode is a combination of RawMidiMode flags.
+func OpenRawMidi(name string, input, output bool, mode int) (*RawMidi, error) {
+	rawmidi := &RawMidi{name: name}
+
+	var inputp, outputp **C.snd_rawmidi_t
+	if input {
+		inputp = &rawmidi.cInput
+	}
+	if output {
+		outputp = &rawmidi.cOutput
+	}
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	err := C.snd_rawmidi_open(inputp, outputp, cName, C.int(mode))
+	if err < 0 {
+		return nil, rawmidi.newError("open rawmidi", err)
+	}
+
+	return rawmidi, nil
+}
+
+// Close closes both streams of the device.
+func (rawmidi *RawMidi) Close() {
+	if rawmidi.cInput != nil {
+		C.snd_rawmidi_close(rawmidi.cInput)
+		rawmidi.cInput = nil
+	}
+	if rawmidi.cOutput != nil {
+		C.snd_rawmidi_close(rawmidi.cOutput)
+		rawmidi.cOutput = nil
+	}
+}
+
+// newError returns Error for the failed operation on the device.
+func (rawmidi *RawMidi) newError(op string, code C.int) error {
+	return newDeviceError(op, rawmidi.name, code)
+}
+
+// stream returns the opened stream of the direction.
+func (rawmidi *RawMidi) stream(stream RawMidiStream) (*C.snd_rawmidi_t, error) {
+	cRawmidi := rawmidi.cOutput
+	if stream == RawMidiStreamInput {
+		cRawmidi = rawmidi.cInput
+	}
+	if cRawmidi == nil {
+		return nil, rawmidi.newError("get rawmidi stream", -C.EBADF)
+	}
+
+	return cRawmidi, nil
+}
+
+// Read reads received MIDI bytes. It blocks till some bytes are received,
+// unless the device is in nonblocking mode.
+func (rawmidi *RawMidi) Read(buf []byte) (int, error) {
+	cInput, err := rawmidi.stream(RawMidiStreamInput)
+	if err != nil {
+		return 0, err
+	}
+	if len(buf) == 0 {
+		return 0, nil
+	}
+
+	n := C.snd_rawmidi_read(cInput, unsafe.Pointer(&buf[0]), C.size_t(len(buf)))
+	if n < 0 {
+		return 0, rawmidi.newError("read", C.int(n))
+	}
+
+	return int(n), nil
+}
+
+// Write writes MIDI bytes to the output buffer and returns the number of
+// bytes written.
+func (rawmidi *RawMidi) Write(buf []byte) (int, error) {
+	cOutput, err := rawmidi.stream(RawMidiStreamOutput)
+	if err != nil {
+		return 0, err
+	}
+
+	wrote := 0
+	for wrote < len(buf) {
+		n := C.snd_rawmidi_write(cOutput, unsafe.Pointer(&buf[wrote]), C.size_t(len(buf)-wrote))
+		if n < 0 {
+			return wrote, rawmidi.newError("write", C.int(n))
+		}
+		wrote += int(n)
+	}
+
+	return wrote, nil
+}
+
+// SetNonblock switches both streams to nonblocking or blocking mode.
+// Nonblocking Read and Write return ErrWouldBlock instead of waiting.
+func (rawmidi *RawMidi) SetNonblock(nonblock bool) error {
+	for _, cRawmidi := range []*C.snd_rawmidi_t{rawmidi.cInput, rawmidi.cOutput} {
+		if cRawmidi == nil {
+			continue
+		}
+		err := C.snd_rawmidi_nonblock(cRawmidi, cBool(nonblock))
+		if err < 0 {
+			return rawmidi.newError("set nonblocking mode", err)
+		}
+	}
+
+	return nil
+}
+
+// Drain waits till all output bytes are sent.
+func (rawmidi *RawMidi) Drain() error {
+	cOutput, err := rawmidi.stream(RawMidiStreamOutput)
+	if err != nil {
+		return err
+	}
+
+	cErr := C.snd_rawmidi_drain(cOutput)
+	if cErr < 0 {
+		return rawmidi.newError("drain", cErr)
+	}
+
+	return nil
+}
+
+// Drop discards pending bytes of both streams.
+func (rawmidi *RawMidi) Drop() error {
+	for _, cRawmidi := range []*C.snd_rawmidi_t{rawmidi.cInput, rawmidi.cOutput} {
+		if cRawmidi == nil {
+			continue
+		}
+		err := C.snd_rawmidi_drop(cRawmidi)
+		if err < 0 {
+			return rawmidi.newError("drop", err)
+		}
+	}
+
+	return nil
+}
+
+// Params returns buffer parameters of the stream.
+func (rawmidi *RawMidi) Params(stream RawMidiStream) (*RawMidiParams, error) {
+	cRawmidi, err := rawmidi.stream(stream)
+	if err != nil {
+		return nil, err
+	}
+
+	var cParams *C.snd_rawmidi_params_t
+	cErr := C.snd_rawmidi_params_malloc(&cParams)
+	if cErr < 0 {
+		return nil, rawmidi.newError("allocate rawmidi parameters", cErr)
+	}
+	defer C.snd_rawmidi_params_free(cParams)
+
+	cErr = C.snd_rawmidi_params_current(cRawmidi, cParams)
+	if cErr < 0 {
+		return nil, rawmidi.newError("get rawmidi parameters", cErr)
+	}
+
+	return &RawMidiParams{
+		BufferSize:      int(C.snd_rawmidi_params_get_buffer_size(cParams)),
+		AvailMin:        int(C.snd_rawmidi_params_get_avail_min(cParams)),
+		NoActiveSensing: C.snd_rawmidi_params_get_no_active_sensing(cParams) != 0,
+	}, nil
+}
+
+// SetParams sets buffer parameters of the stream.
+func (rawmidi *RawMidi) SetParams(stream RawMidiStream, params *RawMidiParams) error {
+	cRawmidi, err := rawmidi.stream(stream)
+	if err != nil {
+		return err
+	}
+
+	var cParams *C.snd_rawmidi_params_t
+	cErr := C.snd_rawmidi_params_malloc(&cParams)
+	if cErr < 0 {
+		return rawmidi.newError("allocate rawmidi parameters", cErr)
+	}
+	defer C.snd_rawmidi_params_free(cParams)
+
+	cErr = C.snd_rawmidi_params_current(cRawmidi, cParams)
+	if cErr < 0 {
+		return rawmidi.newError("get rawmidi parameters", cErr)
+	}
+
+	cErr = C.snd_rawmidi_params_set_buffer_size(cRawmidi, cParams, C.size_t(params.BufferSize))
+	if cErr < 0 {
+		return rawmidi.newError("set buffer size", cErr)
+	}
+
+	cErr = C.snd_rawmidi_params_set_avail_min(cRawmidi, cParams, C.size_t(params.AvailMin))
+	if cErr < 0 {
+		return rawmidi.newError("set avail min", cErr)
+	}
+
+	cErr = C.snd_rawmidi_params_set_no_active_sensing(cRawmidi, cParams, cBool(params.NoActiveSensing))
+	if cErr < 0 {
+		return rawmidi.newError("set no active sensing", cErr)
+	}
+
+	cErr = C.snd_rawmidi_params(cRawmidi, cParams)
+	if cErr < 0 {
+		return rawmidi.newError("set rawmidi parameters", cErr)
+	}
+
+	return nil
+}
+
+// RawMidiDevices returns raw MIDI subdevices of the card.
+func (ctl *Ctl) RawMidiDevices() ([]RawMidiDevice, error) {
+	var cInfo *C.snd_rawmidi_info_t
+	err := C.snd_rawmidi_info_malloc(&cInfo)
+	if err < 0 {
+		return nil, ctl.newError("allocate rawmidi info", err)
+	}
+	defer C.snd_rawmidi_info_free(cInfo)
+
+	var devices []RawMidiDevice
+
+	device := C.int(-1)
+	for {
+		err = C.snd_ctl_rawmidi_next_device(ctl.cCtl, &device)
+		if err < 0 {
+			return devices, ctl.newError("get next rawmidi device", err)
+		}
+		if device < 0 {
+			break
+		}
+
+		// Subdevice indexes of one device are shared by both streams.
+		first := len(devices)
+		for _, stream := range []RawMidiStream{RawMidiStreamOutput, RawMidiStreamInput} {
+			C.snd_rawmidi_info_set_device(cInfo, C.uint(device))
+			C.snd_rawmidi_info_set_stream(cInfo, C.snd_rawmidi_stream_t(stream))
+			C.snd_rawmidi_info_set_subdevice(cInfo, 0)
+			if C.snd_ctl_rawmidi_info(ctl.cCtl, cInfo) < 0 {
+				// Device has no such stream.
+				continue
+			}
+
+			count := int(C.snd_rawmidi_info_get_subdevices_count(cInfo))
+			for sub := 0; sub < count; sub++ {
+				C.snd_rawmidi_info_set_subdevice(cInfo, C.uint(sub))
+				err = C.snd_ctl_rawmidi_info(ctl.cCtl, cInfo)
+				if err < 0 {
+					return devices, ctl.newError("get rawmidi info", err)
+				}
+
+				for len(devices)-first <= sub {
+					devices = append(devices, RawMidiDevice{
+						Card:      int(C.snd_rawmidi_info_get_card(cInfo)),
+						Device:    int(device),
+						Subdevice: len(devices) - first,
+						ID:        C.GoString(C.snd_rawmidi_info_get_id(cInfo)),
+						Name:      C.GoString(C.snd_rawmidi_info_get_name(cInfo)),
+					})
+				}
+
+				entry := &devices[first+sub]
+				entry.SubdeviceName = C.GoString(C.snd_rawmidi_info_get_subdevice_name(cInfo))
+				if stream == RawMidiStreamInput {
+					entry.Input = true
+				} else {
+					entry.Output = true
+				}
+			}
+		}
+	}
+
+	return devices, nil
+}
+
+// ListRawMidiDevices returns raw MIDI subdevices of all cards.
+func ListRawMidiDevices() ([]RawMidiDevice, error) {
+	cards, err := ListCards()
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []RawMidiDevice
+	for _, card := range cards {
+		ctl, err := OpenCtl(fmt.Sprintf("hw:%d", card.Index))
+		if err != nil {
+			return devices, err
+		}
+
+		cardDevices, err := ctl.RawMidiDevices()
+		ctl.Close()
+		if err != nil {
+			return devices, err
+		}
+
+		devices = append(devices, cardDevices...)
+	}
+
+	return devices, nil
+}
@@ -0,0 +1,48 @@
+package alsa
+
+import (
+	"testing"
+)
+
+func TestRawMidi(t *testing.T) {
+	devices, err := ListRawMidiDevices()
+	if err != nil {
+		t.Fatalf("ListRawMidiDevices failed. %s", err)
+	}
+
+	var device *RawMidiDevice
+	for i := range devices {
+		if devices[i].Output {
+			device = &devices[i]
+			break
+		}
+	}
+	if device == nil {
+		t.Skip("No raw MIDI output device")
+	}
+
+	rawmidi, err := OpenRawMidi(device.HwName(), false, true, RawMidiModeNonblock)
+	if err != nil {
+		t.Fatalf("OpenRawMidi failed. %s", err)
+	}
+	defer rawmidi.Close()
+
+	params, err := rawmidi.Params(RawMidiStreamOutput)
+	if err != nil {
+		t.Fatalf("Params failed. %s", err)
+	}
+	params.NoActiveSensing = true
+	if err = rawmidi.SetParams(RawMidiStreamOutput, params); err != nil {
+		t.Fatalf("SetParams failed. %s", err)
+	}
+
+	// All notes off on channel 1.
+	n, err := rawmidi.Write([]byte{0xb0, 0x7b, 0x00})
+	if err != nil || n != 3 {
+		t.Errorf("Write failed, wrote %d. %v", n, err)
+	}
+
+	if _, err = rawmidi.Read(make([]byte, 16)); err == nil {
+		t.Errorf("Read of output only device succeeded")
+	}
+}
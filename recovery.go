@@ -0,0 +1,204 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+import "C"
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// RecoveryMode selects how I/O methods handle xruns and suspends.
+type RecoveryMode int
+
+// Recovery mode constants.
+const (
+	// Recover the stream and retry the transfer. Default.
+	RecoverySilent RecoveryMode = iota
+	// Return the error, leaving the stream in XRUN or SUSPENDED state.
+	RecoveryFailFast
+	// Recover, call Handle.OnXrun and retry the transfer.
+	RecoveryNotify
+	// Recover and retry. Capture reads fill frames lost during an
+	// overrun with silence, so the captured timeline stays continuous.
+	RecoveryInsertSilence
+)
+
+// XrunStats counts glitches seen by the handle I/O methods.
+type XrunStats struct {
+	// Playback buffer ran empty.
+	Underruns int64
+	// Capture buffer overflowed.
+	Overruns int64
+	// Stream was suspended by the system.
+	Suspends int64
+	// Estimated number of frames lost in underruns and overruns.
+	FramesLost int64
+}
+
+// xrunCounters are XrunStats updated from the I/O methods.
+type xrunCounters struct {
+	underruns  atomic.Int64
+	overruns   atomic.Int64
+	suspends   atomic.Int64
+	framesLost atomic.Int64
+}
+
+// XrunStats returns glitch counters of the handle.
+func (handle *Handle) XrunStats() XrunStats {
+	return XrunStats{
+		Underruns:  handle.xruns.underruns.Load(),
+		Overruns:   handle.xruns.overruns.Load(),
+		Suspends:   handle.xruns.suspends.Load(),
+		FramesLost: handle.xruns.framesLost.Load(),
+	}
+}
+
+// ResetXrunStats sets glitch counters of the handle to zero.
+func (handle *Handle) ResetXrunStats() {
+	handle.xruns.underruns.Store(0)
+	handle.xruns.overruns.Store(0)
+	handle.xruns.suspends.Store(0)
+	handle.xruns.framesLost.Store(0)
+}
+
+// maxRecoveries limits consecutive xrun and suspend recoveries of one
+// transfer which move no frames, so a stream failing right after every
+// recovery does not loop forever.
+const maxRecoveries = 8
+
+// transfer calls io till it transfers some frames or fails with an error
+// which is not recovered according to the handle recovery mode. io gets
+// frame offset into the caller buffer and number of frames to transfer.
+// silence, if not nil, fills frames lost in a capture overrun.
+// Returns number of frames transferred including inserted silence.
+func (handle *Handle) transfer(op string, frames int,
+	io func(offset, frames int) C.snd_pcm_sframes_t,
+	silence func(offset, frames int)) (int, error) {
+
+	done := 0
+	recoveries := 0
+	for {
+		n := io(done, frames-done)
+		if n >= 0 {
+			return done + int(n), nil
+		}
+
+		recoveries++
+		if recoveries > maxRecoveries {
+			return done, handle.newError(op, C.int(n))
+		}
+
+		lost, err := handle.recoverXrun(op, C.int(n))
+		if err != nil {
+			return done, err
+		}
+
+		if silence != nil && handle.Recovery == RecoveryInsertSilence &&
+			handle.streamType == StreamTypeCapture {
+			if lost > frames-done {
+				lost = frames - done
+			}
+			silence(done, lost)
+			done += lost
+			if lost > 0 {
+				recoveries = 0
+			}
+			if done == frames {
+				return done, nil
+			}
+		}
+	}
+}
+
+// recoverXrun handles error code of an I/O call according to the handle
+// recovery mode. Returns number of frames lost in an xrun and nil error
+// if the stream was recovered and the call may be retried.
+func (handle *Handle) recoverXrun(op string, code C.int) (int, error) {
+	ioErr := handle.newError(op, code)
+
+	lost := 0
+	switch code {
+	case -C.EPIPE:
+		lost = handle.xrunFrames()
+		if handle.streamType == StreamTypeCapture {
+			handle.xruns.overruns.Add(1)
+		} else {
+			handle.xruns.underruns.Add(1)
+		}
+		handle.xruns.framesLost.Add(int64(lost))
+	case -C.ESTRPIPE:
+		handle.xruns.suspends.Add(1)
+	default:
+		return 0, ioErr
+	}
+
+	if handle.Recovery == RecoveryFailFast {
+		return lost, ioErr
+	}
+
+	var err C.int
+	if code == -C.ESTRPIPE {
+		// Wait until the suspend flag is released.
+		err = C.snd_pcm_resume(handle.cHandle)
+		for err == -C.EAGAIN {
+			time.Sleep(100 * time.Millisecond)
+			err = C.snd_pcm_resume(handle.cHandle)
+		}
+		if err < 0 {
+			// Hardware cannot resume, restart the stream.
+			err = C.snd_pcm_prepare(handle.cHandle)
+		}
+	} else {
+		err = C.snd_pcm_recover(handle.cHandle, code, 1)
+	}
+
+	if err < 0 {
+		return lost, handle.newError("recover", err)
+	}
+
+	if handle.Recovery == RecoveryNotify && handle.OnXrun != nil {
+		handle.OnXrun(ioErr, lost)
+	}
+
+	return lost, nil
+}
+
+// xrunFrames estimates number of frames lost since the stream entered
+// XRUN state.
+func (handle *Handle) xrunFrames() int {
+	var cStatus *C.snd_pcm_status_t
+	if C.snd_pcm_status_malloc(&cStatus) < 0 {
+		return 0
+	}
+	defer C.snd_pcm_status_free(cStatus)
+
+	if C.snd_pcm_status(handle.cHandle, cStatus) < 0 ||
+		C.snd_pcm_status_get_state(cStatus) != C.SND_PCM_STATE_XRUN {
+		return 0
+	}
+
+	var now, trigger C.snd_timestamp_t
+	C.snd_pcm_status_get_tstamp(cStatus, &now)
+	C.snd_pcm_status_get_trigger_tstamp(cStatus, &trigger)
+
+	elapsed := time.Duration(now.tv_sec-trigger.tv_sec)*time.Second +
+		time.Duration(now.tv_usec-trigger.tv_usec)*time.Microsecond
+	if elapsed < 0 {
+		return 0
+	}
+
+	return int(elapsed.Seconds() * float64(handle.SampleRate))
+}
+
+// fillSilence fills buf with silent samples of the handle sample format.
+func (handle *Handle) fillSilence(buf []byte) {
+	silence := handle.SampleFormat.Silence()
+	if len(silence) == 0 {
+		return
+	}
+
+	for i := 0; i < len(buf); i += len(silence) {
+		copy(buf[i:], silence)
+	}
+}
@@ -0,0 +1,65 @@
+package alsa
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRecoveryFailFast(t *testing.T) {
+	handle := New()
+	err := handle.Open("default", StreamTypePlayback, ModeBlock)
+	if err != nil {
+		t.Fatalf("Open failed. %s", err)
+	}
+	defer handle.Close()
+
+	handle.SampleFormat = SampleFormatS16LE
+	handle.SampleRate = 44100
+	handle.Channels = 2
+	handle.Recovery = RecoveryFailFast
+	err = handle.ApplyHwParams()
+	if err != nil {
+		t.Fatalf("ApplyHwParams failed. %s", err)
+	}
+
+	buf := make([]byte, handle.Buffersize*handle.FrameSize())
+	if _, err = handle.Write(buf); err != nil {
+		t.Fatalf("Write failed. %s", err)
+	}
+
+	// Let the buffer run empty.
+	time.Sleep(time.Duration(2*handle.Buffersize) * time.Second / time.Duration(handle.SampleRate))
+
+	_, err = handle.Write(buf)
+	if !errors.Is(err, ErrUnderrun) {
+		t.Fatalf("Write after underrun returned %v", err)
+	}
+	if stats := handle.XrunStats(); stats.Underruns != 1 {
+		t.Errorf("Underruns counter is %d, expected 1", stats.Underruns)
+	}
+
+	handle.Recovery = RecoveryNotify
+	notified := 0
+	handle.OnXrun = func(err error, framesLost int) {
+		notified++
+	}
+	if _, err = handle.Write(buf); err != nil {
+		t.Fatalf("Write with recovery failed. %s", err)
+	}
+	if notified != 1 {
+		t.Errorf("OnXrun called %d times, expected 1", notified)
+	}
+}
+
+func TestFillSilence(t *testing.T) {
+	handle := New()
+	handle.SampleFormat = SampleFormatU16LE
+
+	buf := make([]byte, 6)
+	handle.fillSilence(buf)
+	if !bytes.Equal(buf, []byte{0, 0x80, 0, 0x80, 0, 0x80}) {
+		t.Errorf("Unexpected silence %v", buf)
+	}
+}
@@ -0,0 +1,531 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+// #include <string.h>
+//
+// static void seq_event_clear(snd_seq_event_t *ev) {
+// 	memset(ev, 0, sizeof(*ev));
+// 	ev->queue = SND_SEQ_QUEUE_DIRECT;
+// }
+//
+// static snd_seq_ev_note_t *seq_event_note(snd_seq_event_t *ev) {
+// 	return &ev->data.note;
+// }
+//
+// static snd_seq_ev_ctrl_t *seq_event_control(snd_seq_event_t *ev) {
+// 	return &ev->data.control;
+// }
+//
+// static void seq_event_set_ext(snd_seq_event_t *ev, void *ptr, unsigned int len) {
+// 	ev->flags &= ~SND_SEQ_EVENT_LENGTH_MASK;
+// 	ev->flags |= SND_SEQ_EVENT_LENGTH_VARIABLE;
+// 	ev->data.ext.ptr = ptr;
+// 	ev->data.ext.len = len;
+// }
+//
+// static void *seq_event_ext_ptr(snd_seq_event_t *ev) {
+// 	return ev->data.ext.ptr;
+// }
+//
+// static unsigned int seq_event_ext_len(snd_seq_event_t *ev) {
+// 	return ev->data.ext.len;
+// }
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"unsafe"
+)
+
+// Sequencer port capability flags.
+type SeqPortCap uint
+
+// Sequencer port capability constants.
+const (
+	// Port can be read from
+	SeqPortCapRead = C.SND_SEQ_PORT_CAP_READ
+	// Port can be written to
+	SeqPortCapWrite = C.SND_SEQ_PORT_CAP_WRITE
+	// Port allows read subscriptions
+	SeqPortCapSubsRead = C.SND_SEQ_PORT_CAP_SUBS_READ
+	// Port allows write subscriptions
+	SeqPortCapSubsWrite = C.SND_SEQ_PORT_CAP_SUBS_WRITE
+	// Port supports read and write at once
+	SeqPortCapDuplex = C.SND_SEQ_PORT_CAP_DUPLEX
+	// Port is hidden from other clients
+	SeqPortCapNoExport = C.SND_SEQ_PORT_CAP_NO_EXPORT
+)
+
+// Sequencer port type flags.
+type SeqPortType uint
+
+// Sequencer port type constants.
+const (
+	// Generic MIDI device
+	SeqPortTypeMidiGeneric = C.SND_SEQ_PORT_TYPE_MIDI_GENERIC
+	// General MIDI compatible device
+	SeqPortTypeMidiGM = C.SND_SEQ_PORT_TYPE_MIDI_GM
+	// Synth device
+	SeqPortTypeSynth = C.SND_SEQ_PORT_TYPE_SYNTH
+	// Hardware port
+	SeqPortTypeHardware = C.SND_SEQ_PORT_TYPE_HARDWARE
+	// Software port
+	SeqPortTypeSoftware = C.SND_SEQ_PORT_TYPE_SOFTWARE
+	// Port generates sound
+	SeqPortTypeSynthesizer = C.SND_SEQ_PORT_TYPE_SYNTHESIZER
+	// Port connects to other devices
+	SeqPortTypePort = C.SND_SEQ_PORT_TYPE_PORT
+	// Port belongs to an application
+	SeqPortTypeApplication = C.SND_SEQ_PORT_TYPE_APPLICATION
+)
+
+// Sequencer client type.
+type SeqClientType C.snd_seq_client_type_t
+
+// Sequencer client type constants.
+const (
+	// Application client
+	SeqClientUser = C.SND_SEQ_USER_CLIENT
+	// Kernel client, e.g. a sound card MIDI port
+	SeqClientKernel = C.SND_SEQ_KERNEL_CLIENT
+)
+
+// Sequencer event type.
+type SeqEventType C.snd_seq_event_type_t
+
+// Sequencer event type constants.
+const (
+	// Note on
+	SeqEventNoteOn = C.SND_SEQ_EVENT_NOTEON
+	// Note off
+	SeqEventNoteOff = C.SND_SEQ_EVENT_NOTEOFF
+	// Key pressure change (aftertouch)
+	SeqEventKeyPress = C.SND_SEQ_EVENT_KEYPRESS
+	// Controller change
+	SeqEventController = C.SND_SEQ_EVENT_CONTROLLER
+	// Program change
+	SeqEventPgmChange = C.SND_SEQ_EVENT_PGMCHANGE
+	// Channel pressure
+	SeqEventChanPress = C.SND_SEQ_EVENT_CHANPRESS
+	// Pitch bend, value -8192-8191
+	SeqEventPitchBend = C.SND_SEQ_EVENT_PITCHBEND
+	// System exclusive data
+	SeqEventSysEx = C.SND_SEQ_EVENT_SYSEX
+	// MIDI clock
+	SeqEventClock = C.SND_SEQ_EVENT_CLOCK
+	// MIDI start
+	SeqEventStart = C.SND_SEQ_EVENT_START
+	// MIDI continue
+	SeqEventContinue = C.SND_SEQ_EVENT_CONTINUE
+	// MIDI stop
+	SeqEventStop = C.SND_SEQ_EVENT_STOP
+	// Port was connected
+	SeqEventPortSubscribed = C.SND_SEQ_EVENT_PORT_SUBSCRIBED
+	// Port was disconnected
+	SeqEventPortUnsubscribed = C.SND_SEQ_EVENT_PORT_UNSUBSCRIBED
+)
+
+// SeqAddr is a sequencer client and port address.
+type SeqAddr struct {
+	// Client number, e.g. 128 for the first user client.
+	Client int
+	// Port number within the client.
+	Port int
+}
+
+// SeqAddrSubscribers sends an event to all subscribers of the source port.
+var SeqAddrSubscribers = SeqAddr{Client: C.SND_SEQ_ADDRESS_SUBSCRIBERS, Port: C.SND_SEQ_ADDRESS_UNKNOWN}
+
+// String returns the address in aconnect format, e.g. "128:0".
+func (addr SeqAddr) String() string {
+	return fmt.Sprintf("%d:%d", addr.Client, addr.Port)
+}
+
+// SeqClientInfo describes a sequencer client and its ports.
+type SeqClientInfo struct {
+	// Client number.
+	Client int
+	// Client name, e.g. "Midi Through".
+	Name string
+	// Kernel or user client.
+	Type SeqClientType
+	// Ports of the client.
+	Ports []SeqPortInfo
+}
+
+// SeqPortInfo describes a sequencer port.
+type SeqPortInfo struct {
+	// Client and port address of the port.
+	Addr SeqAddr
+	// Port name, e.g. "Midi Through Port-0".
+	Name string
+	// Read, write and subscription capabilities.
+	Capability SeqPortCap
+	// Port type flags, e.g. a MIDI hardware port.
+	Type SeqPortType
+}
+
+// SeqEvent is a sequencer event. Fields used depend on the event type.
+type SeqEvent struct {
+	Type SeqEventType
+	// Sending client and port; set by the sequencer on send.
+	Source SeqAddr
+	// Receiving port, or SeqAddrSubscribers.
+	Dest SeqAddr
+	// Channel 0-15 of channel events.
+	Channel int
+	// Note and velocity of note events.
+	Note     int
+	Velocity int
+	// Controller number of controller events.
+	Param int
+	// Controller, program, pressure or pitch bend value.
+	Value int
+	// Data of SysEx events, including the 0xF0 and 0xF7 bytes.
+	Data []byte
+}
+
+// Seq is a sequencer client.
+type Seq struct {
+	cSeq *C.snd_seq_t
+	name string
+}
+
+// OpenSeq opens a sequencer client for input and output and sets its name.
+func OpenSeq(clientName string) (*Seq, error) {
+	seq := &Seq{name: "default"}
+
+	cName := C.CString(seq.name)
+	defer C.free(unsafe.Pointer(cName))
+
+	err := C.snd_seq_open(&seq.cSeq, cName, C.SND_SEQ_OPEN_DUPLEX, 0)
+	if err < 0 {
+		return nil, seq.newError("open sequencer", err)
+	}
+
+	if err := seq.SetClientName(clientName); err != nil {
+		seq.Close()
+		return nil, err
+	}
+
+	return seq, nil
+}
+
+// Close closes the client, removing its ports and connections.
+func (seq *Seq) Close() {
+	if seq.cSeq != nil {
+		C.snd_seq_close(seq.cSeq)
+		seq.cSeq = nil
+	}
+}
+
+// newError returns Error for the failed operation on the sequencer.
+func (seq *Seq) newError(op string, code C.int) error {
+	return newDeviceError(op, seq.name, code)
+}
+
+// Client returns the client number.
+func (seq *Seq) Client() int {
+	return int(C.snd_seq_client_id(seq.cSeq))
+}
+
+// SetClientName sets the client name shown to other clients.
+func (seq *Seq) SetClientName(name string) error {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	err := C.snd_seq_set_client_name(seq.cSeq, cName)
+	if err < 0 {
+		return seq.newError("set client name", err)
+	}
+
+	return nil
+}
+
+// CreatePort creates a port and returns its number.
+func (seq *Seq) CreatePort(name string, caps SeqPortCap, portType SeqPortType) (int, error) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	port := C.snd_seq_create_simple_port(seq.cSeq, cName, C.uint(caps), C.uint(portType))
+	if port < 0 {
+		return 0, seq.newError("create port", port)
+	}
+
+	return int(port), nil
+}
+
+// DeletePort deletes the port.
+func (seq *Seq) DeletePort(port int) error {
+	err := C.snd_seq_delete_simple_port(seq.cSeq, C.int(port))
+	if err < 0 {
+		return seq.newError("delete port", err)
+	}
+
+	return nil
+}
+
+// Clients returns all sequencer clients with their ports.
+func (seq *Seq) Clients() ([]SeqClientInfo, error) {
+	var cClient *C.snd_seq_client_info_t
+	err := C.snd_seq_client_info_malloc(&cClient)
+	if err < 0 {
+		return nil, seq.newError("allocate client info", err)
+	}
+	defer C.snd_seq_client_info_free(cClient)
+
+	var cPort *C.snd_seq_port_info_t
+	err = C.snd_seq_port_info_malloc(&cPort)
+	if err < 0 {
+		return nil, seq.newError("allocate port info", err)
+	}
+	defer C.snd_seq_port_info_free(cPort)
+
+	var clients []SeqClientInfo
+
+	C.snd_seq_client_info_set_client(cClient, -1)
+	for C.snd_seq_query_next_client(seq.cSeq, cClient) >= 0 {
+		client := SeqClientInfo{
+			Client: int(C.snd_seq_client_info_get_client(cClient)),
+			Name:   C.GoString(C.snd_seq_client_info_get_name(cClient)),
+			Type:   SeqClientType(C.snd_seq_client_info_get_type(cClient)),
+		}
+
+		C.snd_seq_port_info_set_client(cPort, C.int(client.Client))
+		C.snd_seq_port_info_set_port(cPort, -1)
+		for C.snd_seq_query_next_port(seq.cSeq, cPort) >= 0 {
+			client.Ports = append(client.Ports, SeqPortInfo{
+				Addr: SeqAddr{
+					Client: int(C.snd_seq_port_info_get_client(cPort)),
+					Port:   int(C.snd_seq_port_info_get_port(cPort)),
+				},
+				Name:       C.GoString(C.snd_seq_port_info_get_name(cPort)),
+				Capability: SeqPortCap(C.snd_seq_port_info_get_capability(cPort)),
+				Type:       SeqPortType(C.snd_seq_port_info_get_type(cPort)),
+			})
+		}
+
+		clients = append(clients, client)
+	}
+
+	return clients, nil
+}
+
+// Connect subscribes dest to events of sender, like aconnect.
+func (seq *Seq) Connect(sender, dest SeqAddr) error {
+	return seq.subscribe("connect", sender, dest, func(cSub *C.snd_seq_port_subscribe_t) C.int {
+		return C.snd_seq_subscribe_port(seq.cSeq, cSub)
+	})
+}
+
+// Disconnect removes subscription of dest to events of sender.
+func (seq *Seq) Disconnect(sender, dest SeqAddr) error {
+	return seq.subscribe("disconnect", sender, dest, func(cSub *C.snd_seq_port_subscribe_t) C.int {
+		return C.snd_seq_unsubscribe_port(seq.cSeq, cSub)
+	})
+}
+
+// subscribe runs subscription call for sender and dest.
+func (seq *Seq) subscribe(op string, sender, dest SeqAddr, call func(*C.snd_seq_port_subscribe_t) C.int) error {
+	var cSub *C.snd_seq_port_subscribe_t
+	err := C.snd_seq_port_subscribe_malloc(&cSub)
+	if err < 0 {
+		return seq.newError("allocate subscription", err)
+	}
+	defer C.snd_seq_port_subscribe_free(cSub)
+
+	cSender := sender.cAddr()
+	cDest := dest.cAddr()
+	C.snd_seq_port_subscribe_set_sender(cSub, &cSender)
+	C.snd_seq_port_subscribe_set_dest(cSub, &cDest)
+
+	err = call(cSub)
+	if err < 0 {
+		return seq.newError(op+" "+sender.String()+" to "+dest.String(), err)
+	}
+
+	return nil
+}
+
+// SendDirect sends the event from the port immediately, bypassing the
+// output buffer.
+func (seq *Seq) SendDirect(port int, event *SeqEvent) error {
+	return seq.output("send event", port, event, nil, func(cEvent *C.snd_seq_event_t) C.int {
+		return C.snd_seq_event_output_direct(seq.cSeq, cEvent)
+	})
+}
+
+// Send puts the event from the port into the output buffer. Buffered
+// events are delivered by Drain or when the buffer gets full.
+func (seq *Seq) Send(port int, event *SeqEvent) error {
+	return seq.output("send event", port, event, nil, func(cEvent *C.snd_seq_event_t) C.int {
+		return C.snd_seq_event_output(seq.cSeq, cEvent)
+	})
+}
+
+// Drain delivers all buffered events.
+func (seq *Seq) Drain() error {
+	err := C.snd_seq_drain_output(seq.cSeq)
+	if err < 0 {
+		return seq.newError("drain output", err)
+	}
+
+	return nil
+}
+
+// output fills ALSA event from the event and outputs it. Event is
+// delivered directly when schedule is nil.
+func (seq *Seq) output(op string, port int, event *SeqEvent, schedule *SeqSchedule, out func(*C.snd_seq_event_t) C.int) error {
+	var cEvent C.snd_seq_event_t
+	C.seq_event_clear(&cEvent)
+
+	if schedule != nil {
+		schedule.apply(&cEvent)
+	}
+
+	cEvent._type = C.snd_seq_event_type_t(event.Type)
+	cEvent.source.port = C.uchar(port)
+	cEvent.dest = event.Dest.cAddr()
+
+	switch event.Type {
+	case SeqEventNoteOn, SeqEventNoteOff, SeqEventKeyPress:
+		note := C.seq_event_note(&cEvent)
+		note.channel = C.uchar(event.Channel)
+		note.note = C.uchar(event.Note)
+		note.velocity = C.uchar(event.Velocity)
+	case SeqEventController, SeqEventPgmChange, SeqEventChanPress, SeqEventPitchBend:
+		control := C.seq_event_control(&cEvent)
+		control.channel = C.uchar(event.Channel)
+		control.param = C.uint(event.Param)
+		control.value = C.int(event.Value)
+	case SeqEventSysEx:
+		if len(event.Data) == 0 {
+			return seq.newError(op, -C.EINVAL)
+		}
+		// Output copies the data, so it may be freed right after.
+		data := C.CBytes(event.Data)
+		defer C.free(data)
+		C.seq_event_set_ext(&cEvent, data, C.uint(len(event.Data)))
+	}
+
+	err := out(&cEvent)
+	if err < 0 {
+		return seq.newError(op, err)
+	}
+
+	return nil
+}
+
+// Events returns events received by the client ports on the channel. The
+// client is switched to nonblocking mode, so Send may return ErrWouldBlock
+// while the channel is open. The channel is closed when ctx is done or
+// reading events fails. The client must not be closed before the channel
+// is.
+func (seq *Seq) Events(ctx context.Context) (<-chan SeqEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	count := C.snd_seq_poll_descriptors_count(seq.cSeq, C.POLLIN)
+	if count <= 0 {
+		return nil, seq.newError("get poll descriptors count", -C.EINVAL)
+	}
+
+	fds := make([]C.struct_pollfd, count)
+	filled := C.snd_seq_poll_descriptors(seq.cSeq, &fds[0], C.uint(count), C.POLLIN)
+	if filled < 0 {
+		return nil, seq.newError("get poll descriptors", filled)
+	}
+	fds = fds[:filled]
+
+	cErr := C.snd_seq_nonblock(seq.cSeq, 1)
+	if cErr < 0 {
+		return nil, seq.newError("set nonblocking mode", cErr)
+	}
+
+	poller, err := NewPoller()
+	if err != nil {
+		C.snd_seq_nonblock(seq.cSeq, 0)
+		return nil, err
+	}
+
+	events := make(chan SeqEvent)
+	go func() {
+		defer close(events)
+		defer C.snd_seq_nonblock(seq.cSeq, 0)
+		defer wakeOnDone(ctx, poller)()
+
+		for {
+			err := poller.poll(fds, -1)
+			if err == ErrWoken {
+				if ctx.Err() != nil {
+					return
+				}
+				continue
+			}
+			if err != nil {
+				return
+			}
+
+			for {
+				var cEvent *C.snd_seq_event_t
+				cErr := C.snd_seq_event_input(seq.cSeq, &cEvent)
+				if cErr == -C.EAGAIN {
+					break
+				}
+				if cErr == -C.ENOSPC {
+					// Input overrun; some events were lost.
+					continue
+				}
+				if cErr < 0 {
+					return
+				}
+
+				select {
+				case events <- goSeqEvent(cEvent):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// cAddr returns ALSA address.
+func (addr SeqAddr) cAddr() C.snd_seq_addr_t {
+	return C.snd_seq_addr_t{client: C.uchar(addr.Client), port: C.uchar(addr.Port)}
+}
+
+// goSeqAddr returns address from the ALSA one.
+func goSeqAddr(cAddr C.snd_seq_addr_t) SeqAddr {
+	return SeqAddr{Client: int(cAddr.client), Port: int(cAddr.port)}
+}
+
+// goSeqEvent returns event from the ALSA one, copying its data.
+func goSeqEvent(cEvent *C.snd_seq_event_t) SeqEvent {
+	event := SeqEvent{
+		Type:   SeqEventType(cEvent._type),
+		Source: goSeqAddr(cEvent.source),
+		Dest:   goSeqAddr(cEvent.dest),
+	}
+
+	switch event.Type {
+	case SeqEventNoteOn, SeqEventNoteOff, SeqEventKeyPress:
+		note := C.seq_event_note(cEvent)
+		event.Channel = int(note.channel)
+		event.Note = int(note.note)
+		event.Velocity = int(note.velocity)
+	case SeqEventController, SeqEventPgmChange, SeqEventChanPress, SeqEventPitchBend:
+		control := C.seq_event_control(cEvent)
+		event.Channel = int(control.channel)
+		event.Param = int(control.param)
+		event.Value = int(control.value)
+	case SeqEventSysEx:
+		event.Data = C.GoBytes(C.seq_event_ext_ptr(cEvent), C.int(C.seq_event_ext_len(cEvent)))
+	}
+
+	return event
+}
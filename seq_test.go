@@ -0,0 +1,76 @@
+package alsa
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSeqLoopback(t *testing.T) {
+	seq, err := OpenSeq("alsa-cgo test")
+	if err != nil {
+		t.Fatalf("OpenSeq failed. %s", err)
+	}
+	defer seq.Close()
+
+	out, err := seq.CreatePort("out", SeqPortCapRead|SeqPortCapSubsRead, SeqPortTypeMidiGeneric|SeqPortTypeApplication)
+	if err != nil {
+		t.Fatalf("CreatePort failed. %s", err)
+	}
+	in, err := seq.CreatePort("in", SeqPortCapWrite|SeqPortCapSubsWrite, SeqPortTypeMidiGeneric|SeqPortTypeApplication)
+	if err != nil {
+		t.Fatalf("CreatePort failed. %s", err)
+	}
+
+	clients, err := seq.Clients()
+	if err != nil {
+		t.Fatalf("Clients failed. %s", err)
+	}
+	found := false
+	for _, client := range clients {
+		if client.Client == seq.Client() {
+			found = client.Name == "alsa-cgo test" && len(client.Ports) == 2
+		}
+	}
+	if !found {
+		t.Errorf("Client %d with two ports is not listed", seq.Client())
+	}
+
+	outAddr := SeqAddr{Client: seq.Client(), Port: out}
+	inAddr := SeqAddr{Client: seq.Client(), Port: in}
+	if err = seq.Connect(outAddr, inAddr); err != nil {
+		t.Fatalf("Connect failed. %s", err)
+	}
+	defer seq.Disconnect(outAddr, inAddr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := seq.Events(ctx)
+	if err != nil {
+		t.Fatalf("Events failed. %s", err)
+	}
+
+	sent := []SeqEvent{
+		{Type: SeqEventNoteOn, Dest: SeqAddrSubscribers, Channel: 2, Note: 60, Velocity: 100},
+		{Type: SeqEventPitchBend, Dest: SeqAddrSubscribers, Channel: 2, Value: -200},
+		{Type: SeqEventSysEx, Dest: SeqAddrSubscribers, Data: []byte{0xf0, 0x7e, 0x7f, 0x09, 0x01, 0xf7}},
+	}
+	for i := range sent {
+		if err = seq.SendDirect(out, &sent[i]); err != nil {
+			t.Fatalf("SendDirect failed. %s", err)
+		}
+	}
+
+	for _, expected := range sent {
+		select {
+		case event := <-events:
+			if event.Type != expected.Type || event.Source != outAddr || event.Channel != expected.Channel ||
+				event.Note != expected.Note || event.Value != expected.Value || !bytes.Equal(event.Data, expected.Data) {
+				t.Errorf("Received %+v, expected %+v", event, expected)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Event %v not received", expected.Type)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+//
+// static void seq_event_schedule_tick(snd_seq_event_t *ev, int queue, int relative, snd_seq_tick_time_t tick) {
+// 	ev->flags &= ~(SND_SEQ_TIME_STAMP_MASK | SND_SEQ_TIME_MODE_MASK);
+// 	ev->flags |= SND_SEQ_TIME_STAMP_TICK;
+// 	ev->flags |= relative ? SND_SEQ_TIME_MODE_REL : SND_SEQ_TIME_MODE_ABS;
+// 	ev->time.tick = tick;
+// 	ev->queue = queue;
+// }
+//
+// static void seq_event_schedule_real(snd_seq_event_t *ev, int queue, int relative, unsigned int sec, unsigned int nsec) {
+// 	ev->flags &= ~(SND_SEQ_TIME_STAMP_MASK | SND_SEQ_TIME_MODE_MASK);
+// 	ev->flags |= SND_SEQ_TIME_STAMP_REAL;
+// 	ev->flags |= relative ? SND_SEQ_TIME_MODE_REL : SND_SEQ_TIME_MODE_ABS;
+// 	ev->time.time.tv_sec = sec;
+// 	ev->time.time.tv_nsec = nsec;
+// 	ev->queue = queue;
+// }
+import "C"
+
+import (
+	"time"
+	"unsafe"
+)
+
+// SeqSchedule is the delivery time of a scheduled event.
+type SeqSchedule struct {
+	// Queue which delivers the event.
+	Queue int
+	// Position in queue ticks, used unless Real is set.
+	Tick uint32
+	// Position in real time, used when Real is set.
+	Time time.Duration
+	// Schedule by Time instead of Tick.
+	Real bool
+	// Position is relative to the current queue position.
+	Relative bool
+}
+
+// SeqQueueStatus is the status of a sequencer queue.
+type SeqQueueStatus struct {
+	// Queue number.
+	Queue int
+	// Number of events waiting in the queue.
+	Events int
+	// Current position in ticks.
+	Tick uint32
+	// Current position in real time.
+	Time time.Duration
+	// Queue is running.
+	Running bool
+}
+
+// AllocQueue allocates a named queue and returns its number. The queue is
+// stopped, with tempo 120 BPM and 96 PPQ.
+func (seq *Seq) AllocQueue(name string) (int, error) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	queue := C.snd_seq_alloc_named_queue(seq.cSeq, cName)
+	if queue < 0 {
+		return 0, seq.newError("allocate queue", queue)
+	}
+
+	return int(queue), nil
+}
+
+// FreeQueue frees the queue, dropping its events.
+func (seq *Seq) FreeQueue(queue int) error {
+	err := C.snd_seq_free_queue(seq.cSeq, C.int(queue))
+	if err < 0 {
+		return seq.newError("free queue", err)
+	}
+
+	return nil
+}
+
+// StartQueue starts the queue from position zero.
+func (seq *Seq) StartQueue(queue int) error {
+	return seq.controlQueue("start queue", queue, C.SND_SEQ_EVENT_START)
+}
+
+// StopQueue stops the queue, keeping its position.
+func (seq *Seq) StopQueue(queue int) error {
+	return seq.controlQueue("stop queue", queue, C.SND_SEQ_EVENT_STOP)
+}
+
+// ContinueQueue continues the stopped queue from its position.
+func (seq *Seq) ContinueQueue(queue int) error {
+	return seq.controlQueue("continue queue", queue, C.SND_SEQ_EVENT_CONTINUE)
+}
+
+// controlQueue sends the queue control event and delivers it.
+func (seq *Seq) controlQueue(op string, queue int, eventType C.int) error {
+	err := C.snd_seq_control_queue(seq.cSeq, C.int(queue), eventType, 0, nil)
+	if err < 0 {
+		return seq.newError(op, err)
+	}
+
+	return seq.Drain()
+}
+
+// QueueTempo returns the queue tempo as duration of a quarter note and
+// ticks per quarter note.
+func (seq *Seq) QueueTempo(queue int) (tempo time.Duration, ppq int, err error) {
+	var cTempo *C.snd_seq_queue_tempo_t
+	cErr := C.snd_seq_queue_tempo_malloc(&cTempo)
+	if cErr < 0 {
+		return 0, 0, seq.newError("allocate queue tempo", cErr)
+	}
+	defer C.snd_seq_queue_tempo_free(cTempo)
+
+	cErr = C.snd_seq_get_queue_tempo(seq.cSeq, C.int(queue), cTempo)
+	if cErr < 0 {
+		return 0, 0, seq.newError("get queue tempo", cErr)
+	}
+
+	tempo = time.Duration(C.snd_seq_queue_tempo_get_tempo(cTempo)) * time.Microsecond
+	ppq = int(C.snd_seq_queue_tempo_get_ppq(cTempo))
+
+	return tempo, ppq, nil
+}
+
+// SetQueueTempo sets the queue tempo as duration of a quarter note, e.g.
+// 500ms for 120 BPM, and ticks per quarter note. PPQ can only be changed
+// while the queue is stopped.
+func (seq *Seq) SetQueueTempo(queue int, tempo time.Duration, ppq int) error {
+	var cTempo *C.snd_seq_queue_tempo_t
+	err := C.snd_seq_queue_tempo_malloc(&cTempo)
+	if err < 0 {
+		return seq.newError("allocate queue tempo", err)
+	}
+	defer C.snd_seq_queue_tempo_free(cTempo)
+
+	err = C.snd_seq_get_queue_tempo(seq.cSeq, C.int(queue), cTempo)
+	if err < 0 {
+		return seq.newError("get queue tempo", err)
+	}
+
+	C.snd_seq_queue_tempo_set_tempo(cTempo, C.uint(tempo/time.Microsecond))
+	C.snd_seq_queue_tempo_set_ppq(cTempo, C.int(ppq))
+
+	err = C.snd_seq_set_queue_tempo(seq.cSeq, C.int(queue), cTempo)
+	if err < 0 {
+		return seq.newError("set queue tempo", err)
+	}
+
+	return nil
+}
+
+// QueueStatus returns the current status of the queue.
+func (seq *Seq) QueueStatus(queue int) (*SeqQueueStatus, error) {
+	var cStatus *C.snd_seq_queue_status_t
+	err := C.snd_seq_queue_status_malloc(&cStatus)
+	if err < 0 {
+		return nil, seq.newError("allocate queue status", err)
+	}
+	defer C.snd_seq_queue_status_free(cStatus)
+
+	err = C.snd_seq_get_queue_status(seq.cSeq, C.int(queue), cStatus)
+	if err < 0 {
+		return nil, seq.newError("get queue status", err)
+	}
+
+	realTime := C.snd_seq_queue_status_get_real_time(cStatus)
+
+	return &SeqQueueStatus{
+		Queue:   int(C.snd_seq_queue_status_get_queue(cStatus)),
+		Events:  int(C.snd_seq_queue_status_get_events(cStatus)),
+		Tick:    uint32(C.snd_seq_queue_status_get_tick_time(cStatus)),
+		Time:    time.Duration(realTime.tv_sec)*time.Second + time.Duration(realTime.tv_nsec),
+		Running: C.snd_seq_queue_status_get_status(cStatus)&1 != 0,
+	}, nil
+}
+
+// Schedule puts the event from the port into the output buffer for
+// delivery by the queue at the given time. Buffered events reach the queue
+// on Drain. Event destination may be a port of this or any other client.
+func (seq *Seq) Schedule(port int, event *SeqEvent, at SeqSchedule) error {
+	return seq.output("schedule event", port, event, &at, func(cEvent *C.snd_seq_event_t) C.int {
+		return C.snd_seq_event_output(seq.cSeq, cEvent)
+	})
+}
+
+// apply sets the event delivery time.
+func (schedule *SeqSchedule) apply(cEvent *C.snd_seq_event_t) {
+	relative := cBool(schedule.Relative)
+	if !schedule.Real {
+		C.seq_event_schedule_tick(cEvent, C.int(schedule.Queue), relative, C.snd_seq_tick_time_t(schedule.Tick))
+		return
+	}
+
+	sec := schedule.Time / time.Second
+	nsec := schedule.Time % time.Second
+	C.seq_event_schedule_real(cEvent, C.int(schedule.Queue), relative, C.uint(sec), C.uint(nsec))
+}
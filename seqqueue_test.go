@@ -0,0 +1,84 @@
+package alsa
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSeqQueue(t *testing.T) {
+	seq, err := OpenSeq("alsa-cgo queue test")
+	if err != nil {
+		t.Fatalf("OpenSeq failed. %s", err)
+	}
+	defer seq.Close()
+
+	port, err := seq.CreatePort("loop", SeqPortCapRead|SeqPortCapWrite, SeqPortTypeApplication)
+	if err != nil {
+		t.Fatalf("CreatePort failed. %s", err)
+	}
+
+	queue, err := seq.AllocQueue("test")
+	if err != nil {
+		t.Fatalf("AllocQueue failed. %s", err)
+	}
+	defer seq.FreeQueue(queue)
+
+	// 100ms quarter notes of 10 ticks.
+	if err = seq.SetQueueTempo(queue, 100*time.Millisecond, 10); err != nil {
+		t.Fatalf("SetQueueTempo failed. %s", err)
+	}
+	tempo, ppq, err := seq.QueueTempo(queue)
+	if err != nil {
+		t.Fatalf("QueueTempo failed. %s", err)
+	}
+	if tempo != 100*time.Millisecond || ppq != 10 {
+		t.Errorf("Queue tempo %v, ppq %d", tempo, ppq)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := seq.Events(ctx)
+	if err != nil {
+		t.Fatalf("Events failed. %s", err)
+	}
+
+	if err = seq.StartQueue(queue); err != nil {
+		t.Fatalf("StartQueue failed. %s", err)
+	}
+
+	// Half a quarter note later to our own port.
+	event := SeqEvent{Type: SeqEventNoteOn, Dest: SeqAddr{Client: seq.Client(), Port: port}, Note: 64, Velocity: 1}
+	err = seq.Schedule(port, &event, SeqSchedule{Queue: queue, Tick: 5, Relative: true})
+	if err != nil {
+		t.Fatalf("Schedule failed. %s", err)
+	}
+	if err = seq.Drain(); err != nil {
+		t.Fatalf("Drain failed. %s", err)
+	}
+
+	sent := time.Now()
+	select {
+	case received := <-events:
+		if received.Note != 64 {
+			t.Errorf("Received %+v", received)
+		}
+		if elapsed := time.Since(sent); elapsed < 30*time.Millisecond {
+			t.Errorf("Event delivered after %v, expected 50ms", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Scheduled event not received")
+	}
+
+	status, err := seq.QueueStatus(queue)
+	if err != nil {
+		t.Fatalf("QueueStatus failed. %s", err)
+	}
+	if !status.Running || status.Tick < 5 || status.Time <= 0 {
+		t.Errorf("Unexpected queue status %+v", status)
+	}
+
+	if err = seq.StopQueue(queue); err != nil {
+		t.Fatalf("StopQueue failed. %s", err)
+	}
+}
@@ -0,0 +1,123 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+import "C"
+
+// PCM stream state.
+type PCMState C.snd_pcm_state_t
+
+// PCM state constants.
+const (
+	// Open
+	PCMStateOpen = C.SND_PCM_STATE_OPEN
+	// Setup installed
+	PCMStateSetup = C.SND_PCM_STATE_SETUP
+	// Ready to start
+	PCMStatePrepared = C.SND_PCM_STATE_PREPARED
+	// Running
+	PCMStateRunning = C.SND_PCM_STATE_RUNNING
+	// Stopped: underrun (playback) or overrun (capture) detected
+	PCMStateXrun = C.SND_PCM_STATE_XRUN
+	// Draining: running (playback) or stopped (capture)
+	PCMStateDraining = C.SND_PCM_STATE_DRAINING
+	// Paused
+	PCMStatePaused = C.SND_PCM_STATE_PAUSED
+	// Hardware is suspended
+	PCMStateSuspended = C.SND_PCM_STATE_SUSPENDED
+	// Hardware is disconnected
+	PCMStateDisconnected = C.SND_PCM_STATE_DISCONNECTED
+)
+
+// String returns ALSA name of the state, e.g. "RUNNING".
+func (state PCMState) String() string {
+	return C.GoString(C.snd_pcm_state_name(C.snd_pcm_state_t(state)))
+}
+
+// State returns current state of the stream.
+func (handle *Handle) State() PCMState {
+	return PCMState(C.snd_pcm_state(handle.cHandle))
+}
+
+// Prepare prepares the stream for use. Applied after xrun to restart it.
+func (handle *Handle) Prepare() error {
+	err := C.snd_pcm_prepare(handle.cHandle)
+	if err < 0 {
+		return handle.newError("prepare", err)
+	}
+
+	return nil
+}
+
+// Start starts prepared stream explicitly, regardless of the start threshold.
+func (handle *Handle) Start() error {
+	err := C.snd_pcm_start(handle.cHandle)
+	if err < 0 {
+		return handle.newError("start", err)
+	}
+
+	return nil
+}
+
+// Reset drops queued frames and resets the delay to zero, keeping the
+// stream state.
+func (handle *Handle) Reset() error {
+	err := C.snd_pcm_reset(handle.cHandle)
+	if err < 0 {
+		return handle.newError("reset", err)
+	}
+
+	return nil
+}
+
+// Resume resumes the stream after system suspend. Returns ErrWouldBlock
+// while the hardware is not ready yet; hardware which cannot resume
+// reports an error and the stream must be prepared again.
+func (handle *Handle) Resume() error {
+	err := C.snd_pcm_resume(handle.cHandle)
+	if err < 0 {
+		return handle.newError("resume", err)
+	}
+
+	return nil
+}
+
+// Hwsync synchronizes stream position with the hardware.
+func (handle *Handle) Hwsync() error {
+	err := C.snd_pcm_hwsync(handle.cHandle)
+	if err < 0 {
+		return handle.newError("hwsync", err)
+	}
+
+	return nil
+}
+
+// Rewindable returns number of frames the application position can be
+// safely moved backwards.
+func (handle *Handle) Rewindable() (int, error) {
+	frames := C.snd_pcm_rewindable(handle.cHandle)
+	if frames < 0 {
+		return 0, handle.newError("get rewindable frames", C.int(frames))
+	}
+
+	return int(frames), nil
+}
+
+// Rewind moves application position backwards by at most frames, limited
+// to Rewindable. Returns number of frames actually rewound.
+func (handle *Handle) Rewind(frames int) (int, error) {
+	rewindable, err := handle.Rewindable()
+	if err != nil {
+		return 0, err
+	}
+
+	if rewindable < frames {
+		frames = rewindable
+	}
+
+	rewound := C.snd_pcm_rewind(handle.cHandle, C.snd_pcm_uframes_t(frames))
+	if rewound < 0 {
+		return 0, handle.newError("rewind frames", C.int(rewound))
+	}
+
+	return int(rewound), nil
+}
@@ -0,0 +1,70 @@
+package alsa
+
+import (
+	"testing"
+)
+
+func TestState(t *testing.T) {
+	handle := New()
+	err := handle.Open("default", StreamTypePlayback, ModeBlock)
+	if err != nil {
+		t.Fatalf("Open failed. %s", err)
+	}
+	defer handle.Close()
+
+	if state := handle.State(); state != PCMStateOpen {
+		t.Errorf("State after open is %s", state)
+	}
+
+	handle.SampleFormat = SampleFormatS16LE
+	handle.SampleRate = 44100
+	handle.Channels = 2
+	err = handle.ApplyHwParams()
+	if err != nil {
+		t.Fatalf("ApplyHwParams failed. %s", err)
+	}
+
+	if state := handle.State(); state != PCMStatePrepared {
+		t.Errorf("State after ApplyHwParams is %s", state)
+	}
+
+	// Keep the stream from starting on write.
+	params, err := handle.SwParams()
+	if err != nil {
+		t.Fatalf("SwParams failed. %s", err)
+	}
+	defer params.Free()
+	if err = params.SetStartThreshold(handle.Buffersize); err != nil {
+		t.Fatalf("SetStartThreshold failed. %s", err)
+	}
+	if err = params.Apply(); err != nil {
+		t.Fatalf("Apply failed. %s", err)
+	}
+
+	buf := make([]byte, handle.PeriodSize*handle.FrameSize())
+	if _, err = handle.Write(buf); err != nil {
+		t.Fatalf("Write failed. %s", err)
+	}
+	if state := handle.State(); state != PCMStatePrepared {
+		t.Errorf("State after Write is %s", state)
+	}
+	if err = handle.Start(); err != nil {
+		t.Fatalf("Start failed. %s", err)
+	}
+	if state := handle.State(); state != PCMStateRunning {
+		t.Errorf("State after Start is %s", state)
+	}
+
+	if err = handle.Drop(); err != nil {
+		t.Fatalf("Drop failed. %s", err)
+	}
+	if state := handle.State(); state != PCMStateSetup {
+		t.Errorf("State after Drop is %s", state)
+	}
+	if err = handle.Prepare(); err != nil {
+		t.Fatalf("Prepare failed. %s", err)
+	}
+	if state := handle.State(); state != PCMStatePrepared {
+		t.Errorf("State after Prepare is %s", state)
+	}
+}
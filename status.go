@@ -0,0 +1,122 @@
+package alsa
+
+/*
+#include <alsa/asoundlib.h>
+
+static void alsa_status_set_audio_tstamp_type(snd_pcm_status_t *status, int type) {
+	snd_pcm_audio_tstamp_config_t config;
+
+	memset(&config, 0, sizeof(config));
+	config.type_requested = type;
+	snd_pcm_status_set_audio_htstamp_config(status, &config);
+}
+
+static int alsa_status_audio_tstamp_type(const snd_pcm_status_t *status, int *type) {
+	snd_pcm_audio_tstamp_report_t report;
+
+	memset(&report, 0, sizeof(report));
+	snd_pcm_status_get_audio_htstamp_report(status, &report);
+	*type = report.actual_type;
+
+	return report.valid;
+}
+*/
+import "C"
+
+import (
+	"time"
+)
+
+// Audio timestamp type. Selects the source of the audio timestamp.
+type AudioTimestampType C.snd_pcm_audio_tstamp_type_t
+
+// Audio timestamp type constants.
+const (
+	// DMA time, reported as per hw_ptr
+	AudioTimestampCompat = C.SND_PCM_AUDIO_TSTAMP_TYPE_COMPAT
+	// DMA time, reported in separate field
+	AudioTimestampDefault = C.SND_PCM_AUDIO_TSTAMP_TYPE_DEFAULT
+	// Link time reported by sample or wallclock counter, reset on startup
+	AudioTimestampLink = C.SND_PCM_AUDIO_TSTAMP_TYPE_LINK
+	// Link time reported by sample or wallclock counter, not reset on startup
+	AudioTimestampLinkAbsolute = C.SND_PCM_AUDIO_TSTAMP_TYPE_LINK_ABSOLUTE
+	// Link time estimated indirectly
+	AudioTimestampLinkEstimated = C.SND_PCM_AUDIO_TSTAMP_TYPE_LINK_ESTIMATED
+	// Link time synchronized with system time
+	AudioTimestampLinkSynchronized = C.SND_PCM_AUDIO_TSTAMP_TYPE_LINK_SYNCHRONIZED
+)
+
+// Status is a snapshot of the stream state and position. Timestamps use
+// the clock selected by SwParams.SetTimestampType.
+type Status struct {
+	// Stream state.
+	State PCMState
+	// Time when the stream was started or stopped.
+	TriggerTime time.Time
+	// Time of the snapshot.
+	Time time.Time
+	// Audio time since the stream start, taken together with Time.
+	AudioTime time.Duration
+	// Audio timestamp type actually used by the driver.
+	AudioTimestampType AudioTimestampType
+	// Whether the driver reported AudioTimestampType.
+	AudioTimestampReported bool
+	// Frames ready to be read (capture) or written (playback).
+	Avail int
+	// Maximum Avail since the last status call.
+	AvailMax int
+	// Frames between the application position and the hardware.
+	Delay int
+	// ADC overrange detections count.
+	Overrange int
+}
+
+// Status returns current status of the stream with audio timestamp of
+// AudioTimestampCompat type.
+func (handle *Handle) Status() (*Status, error) {
+	return handle.StatusWithAudioTimestamp(AudioTimestampCompat)
+}
+
+// StatusWithAudioTimestamp returns current status of the stream requesting
+// audio timestamp of the given type. Drivers fall back to a supported type,
+// see Status.AudioTimestampType and HwParams.SupportsAudioTimestampType.
+func (handle *Handle) StatusWithAudioTimestamp(audioType AudioTimestampType) (*Status, error) {
+	var cStatus *C.snd_pcm_status_t
+
+	err := C.snd_pcm_status_malloc(&cStatus)
+	if err < 0 {
+		return nil, handle.newError("allocate status", err)
+	}
+	defer C.snd_pcm_status_free(cStatus)
+
+	C.alsa_status_set_audio_tstamp_type(cStatus, C.int(audioType))
+
+	err = C.snd_pcm_status(handle.cHandle, cStatus)
+	if err < 0 {
+		return nil, handle.newError("get status", err)
+	}
+
+	var trigger, now, audio C.snd_htimestamp_t
+	C.snd_pcm_status_get_trigger_htstamp(cStatus, &trigger)
+	C.snd_pcm_status_get_htstamp(cStatus, &now)
+	C.snd_pcm_status_get_audio_htstamp(cStatus, &audio)
+
+	status := &Status{
+		State:       PCMState(C.snd_pcm_status_get_state(cStatus)),
+		TriggerTime: time.Unix(int64(trigger.tv_sec), int64(trigger.tv_nsec)),
+		Time:        time.Unix(int64(now.tv_sec), int64(now.tv_nsec)),
+		AudioTime:   time.Duration(audio.tv_sec)*time.Second + time.Duration(audio.tv_nsec),
+		Avail:       int(C.snd_pcm_status_get_avail(cStatus)),
+		AvailMax:    int(C.snd_pcm_status_get_avail_max(cStatus)),
+		Delay:       int(C.snd_pcm_status_get_delay(cStatus)),
+		Overrange:   int(C.snd_pcm_status_get_overrange(cStatus)),
+	}
+
+	var audioTypeActual C.int
+	if C.alsa_status_audio_tstamp_type(cStatus, &audioTypeActual) != 0 {
+		status.AudioTimestampType = AudioTimestampType(audioTypeActual)
+		status.AudioTimestampReported = true
+	}
+
+	return status, nil
+}
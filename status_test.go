@@ -0,0 +1,56 @@
+package alsa
+
+import (
+	"testing"
+)
+
+func TestStatus(t *testing.T) {
+	handle := New()
+	err := handle.Open("default", StreamTypePlayback, ModeBlock)
+	if err != nil {
+		t.Fatalf("Open failed. %s", err)
+	}
+	defer handle.Close()
+
+	handle.SampleFormat = SampleFormatS16LE
+	handle.SampleRate = 44100
+	handle.Channels = 2
+	err = handle.ApplyHwParams()
+	if err != nil {
+		t.Fatalf("ApplyHwParams failed. %s", err)
+	}
+
+	params, err := handle.SwParams()
+	if err != nil {
+		t.Fatalf("SwParams failed. %s", err)
+	}
+	defer params.Free()
+	if err = params.SetTimestampMode(TimestampModeEnable); err != nil {
+		t.Fatalf("SetTimestampMode failed. %s", err)
+	}
+	if err = params.SetTimestampType(TimestampTypeMonotonic); err != nil {
+		t.Fatalf("SetTimestampType failed. %s", err)
+	}
+	if err = params.Apply(); err != nil {
+		t.Fatalf("Apply failed. %s", err)
+	}
+
+	buf := make([]byte, handle.PeriodSize*handle.FrameSize())
+	if _, err = handle.Write(buf); err != nil {
+		t.Fatalf("Write failed. %s", err)
+	}
+
+	status, err := handle.Status()
+	if err != nil {
+		t.Fatalf("Status failed. %s", err)
+	}
+	if status.State != PCMStateRunning {
+		t.Errorf("Status state is %s", status.State)
+	}
+	if status.Time.Before(status.TriggerTime) {
+		t.Errorf("Status time %v is before trigger time %v", status.Time, status.TriggerTime)
+	}
+	if status.Delay < 0 || status.Delay > handle.Buffersize {
+		t.Errorf("Invalid delay %d", status.Delay)
+	}
+}
@@ -0,0 +1,263 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+import "C"
+
+// Timestamp mode of the stream.
+type TimestampMode C.snd_pcm_tstamp_t
+
+// Timestamp mode constants.
+const (
+	// No timestamp
+	TimestampModeNone = C.SND_PCM_TSTAMP_NONE
+	// Update timestamp at every hardware position update
+	TimestampModeEnable = C.SND_PCM_TSTAMP_ENABLE
+)
+
+// Timestamp type. Clock used for stream timestamps.
+type TimestampType C.snd_pcm_tstamp_type_t
+
+// Timestamp type constants.
+const (
+	// gettimeofday equivalent, wall clock
+	TimestampTypeGettimeofday = C.SND_PCM_TSTAMP_TYPE_GETTIMEOFDAY
+	// posix_clock_monotonic equivalent
+	TimestampTypeMonotonic = C.SND_PCM_TSTAMP_TYPE_MONOTONIC
+	// monotonic_raw (no NTP)
+	TimestampTypeMonotonicRaw = C.SND_PCM_TSTAMP_TYPE_MONOTONIC_RAW
+)
+
+// SwParams is a software configuration of the stream. Values take effect
+// after Apply.
+type SwParams struct {
+	handle  *Handle
+	cParams *C.snd_pcm_sw_params_t
+}
+
+// SwParams returns the current software configuration of the stream.
+// Hardware parameters must be applied first. Returned value must be
+// released with Free.
+func (handle *Handle) SwParams() (*SwParams, error) {
+	params := &SwParams{handle: handle}
+
+	err := C.snd_pcm_sw_params_malloc(&params.cParams)
+	if err < 0 {
+		return nil, handle.newError("allocate software parameters", err)
+	}
+
+	err = C.snd_pcm_sw_params_current(handle.cHandle, params.cParams)
+	if err < 0 {
+		C.snd_pcm_sw_params_free(params.cParams)
+		return nil, handle.newError("get software parameters", err)
+	}
+
+	return params, nil
+}
+
+// Free releases the software configuration.
+func (params *SwParams) Free() {
+	if params.cParams != nil {
+		C.snd_pcm_sw_params_free(params.cParams)
+		params.cParams = nil
+	}
+}
+
+// Apply installs the software configuration on the stream.
+func (params *SwParams) Apply() error {
+	err := C.snd_pcm_sw_params(params.handle.cHandle, params.cParams)
+	if err < 0 {
+		return params.handle.newError("set software parameters", err)
+	}
+
+	return nil
+}
+
+// Boundary returns the ring pointer boundary in frames. Setting stop
+// threshold to the boundary keeps the stream running through underruns.
+func (params *SwParams) Boundary() (int, error) {
+	var frames C.snd_pcm_uframes_t
+	err := C.snd_pcm_sw_params_get_boundary(params.cParams, &frames)
+	if err < 0 {
+		return 0, params.handle.newError("get boundary", err)
+	}
+
+	return int(frames), nil
+}
+
+// StartThreshold returns number of frames which starts the stream
+// automatically.
+func (params *SwParams) StartThreshold() (int, error) {
+	var frames C.snd_pcm_uframes_t
+	err := C.snd_pcm_sw_params_get_start_threshold(params.cParams, &frames)
+	if err < 0 {
+		return 0, params.handle.newError("get start threshold", err)
+	}
+
+	return int(frames), nil
+}
+
+// SetStartThreshold sets number of frames which starts the stream
+// automatically. For playback it is the number of queued frames, for
+// capture the number of frames requested by a read.
+func (params *SwParams) SetStartThreshold(frames int) error {
+	err := C.snd_pcm_sw_params_set_start_threshold(params.handle.cHandle, params.cParams, C.snd_pcm_uframes_t(frames))
+	if err < 0 {
+		return params.handle.newError("set start threshold", err)
+	}
+
+	return nil
+}
+
+// StopThreshold returns number of available frames which stops the stream.
+func (params *SwParams) StopThreshold() (int, error) {
+	var frames C.snd_pcm_uframes_t
+	err := C.snd_pcm_sw_params_get_stop_threshold(params.cParams, &frames)
+	if err < 0 {
+		return 0, params.handle.newError("get stop threshold", err)
+	}
+
+	return int(frames), nil
+}
+
+// SetStopThreshold sets number of available frames which stops the stream
+// with an xrun.
+func (params *SwParams) SetStopThreshold(frames int) error {
+	err := C.snd_pcm_sw_params_set_stop_threshold(params.handle.cHandle, params.cParams, C.snd_pcm_uframes_t(frames))
+	if err < 0 {
+		return params.handle.newError("set stop threshold", err)
+	}
+
+	return nil
+}
+
+// AvailMin returns minimum number of available frames to consider the
+// stream ready for I/O.
+func (params *SwParams) AvailMin() (int, error) {
+	var frames C.snd_pcm_uframes_t
+	err := C.snd_pcm_sw_params_get_avail_min(params.cParams, &frames)
+	if err < 0 {
+		return 0, params.handle.newError("get avail min", err)
+	}
+
+	return int(frames), nil
+}
+
+// SetAvailMin sets minimum number of available frames to consider the
+// stream ready for I/O, i.e. to wake up Wait.
+func (params *SwParams) SetAvailMin(frames int) error {
+	err := C.snd_pcm_sw_params_set_avail_min(params.handle.cHandle, params.cParams, C.snd_pcm_uframes_t(frames))
+	if err < 0 {
+		return params.handle.newError("set avail min", err)
+	}
+
+	return nil
+}
+
+// SilenceThreshold returns the silence threshold in frames.
+func (params *SwParams) SilenceThreshold() (int, error) {
+	var frames C.snd_pcm_uframes_t
+	err := C.snd_pcm_sw_params_get_silence_threshold(params.cParams, &frames)
+	if err < 0 {
+		return 0, params.handle.newError("get silence threshold", err)
+	}
+
+	return int(frames), nil
+}
+
+// SetSilenceThreshold sets the silence threshold. When free space of the
+// playback buffer reaches it, SilenceSize frames of silence are written.
+func (params *SwParams) SetSilenceThreshold(frames int) error {
+	err := C.snd_pcm_sw_params_set_silence_threshold(params.handle.cHandle, params.cParams, C.snd_pcm_uframes_t(frames))
+	if err < 0 {
+		return params.handle.newError("set silence threshold", err)
+	}
+
+	return nil
+}
+
+// SilenceSize returns the silence size in frames.
+func (params *SwParams) SilenceSize() (int, error) {
+	var frames C.snd_pcm_uframes_t
+	err := C.snd_pcm_sw_params_get_silence_size(params.cParams, &frames)
+	if err < 0 {
+		return 0, params.handle.newError("get silence size", err)
+	}
+
+	return int(frames), nil
+}
+
+// SetSilenceSize sets number of silence frames written when the silence
+// threshold is reached. Setting it to Boundary with zero threshold fills
+// all played area with silence.
+func (params *SwParams) SetSilenceSize(frames int) error {
+	err := C.snd_pcm_sw_params_set_silence_size(params.handle.cHandle, params.cParams, C.snd_pcm_uframes_t(frames))
+	if err < 0 {
+		return params.handle.newError("set silence size", err)
+	}
+
+	return nil
+}
+
+// PeriodEvent reports whether period wakeups are enabled.
+func (params *SwParams) PeriodEvent() (bool, error) {
+	var enabled C.int
+	err := C.snd_pcm_sw_params_get_period_event(params.cParams, &enabled)
+	if err < 0 {
+		return false, params.handle.newError("get period event", err)
+	}
+
+	return enabled != 0, nil
+}
+
+// SetPeriodEvent enables or disables wakeup on every period boundary,
+// independently of AvailMin.
+func (params *SwParams) SetPeriodEvent(enabled bool) error {
+	err := C.snd_pcm_sw_params_set_period_event(params.handle.cHandle, params.cParams, cBool(enabled))
+	if err < 0 {
+		return params.handle.newError("set period event", err)
+	}
+
+	return nil
+}
+
+// TimestampMode returns the timestamp mode.
+func (params *SwParams) TimestampMode() (TimestampMode, error) {
+	var mode C.snd_pcm_tstamp_t
+	err := C.snd_pcm_sw_params_get_tstamp_mode(params.cParams, &mode)
+	if err < 0 {
+		return TimestampModeNone, params.handle.newError("get timestamp mode", err)
+	}
+
+	return TimestampMode(mode), nil
+}
+
+// SetTimestampMode sets the timestamp mode.
+func (params *SwParams) SetTimestampMode(mode TimestampMode) error {
+	err := C.snd_pcm_sw_params_set_tstamp_mode(params.handle.cHandle, params.cParams, C.snd_pcm_tstamp_t(mode))
+	if err < 0 {
+		return params.handle.newError("set timestamp mode", err)
+	}
+
+	return nil
+}
+
+// TimestampType returns the clock used for timestamps.
+func (params *SwParams) TimestampType() (TimestampType, error) {
+	var tstampType C.snd_pcm_tstamp_type_t
+	err := C.snd_pcm_sw_params_get_tstamp_type(params.cParams, &tstampType)
+	if err < 0 {
+		return TimestampTypeGettimeofday, params.handle.newError("get timestamp type", err)
+	}
+
+	return TimestampType(tstampType), nil
+}
+
+// SetTimestampType sets the clock used for timestamps.
+func (params *SwParams) SetTimestampType(tstampType TimestampType) error {
+	err := C.snd_pcm_sw_params_set_tstamp_type(params.handle.cHandle, params.cParams, C.snd_pcm_tstamp_type_t(tstampType))
+	if err < 0 {
+		return params.handle.newError("set timestamp type", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package alsa
+
+import (
+	"testing"
+)
+
+func TestSwParams(t *testing.T) {
+	handle := New()
+	err := handle.Open("default", StreamTypePlayback, ModeBlock)
+	if err != nil {
+		t.Fatalf("Open failed. %s", err)
+	}
+	defer handle.Close()
+
+	handle.SampleFormat = SampleFormatS16LE
+	handle.SampleRate = 44100
+	handle.Channels = 2
+	err = handle.ApplyHwParams()
+	if err != nil {
+		t.Fatalf("ApplyHwParams failed. %s", err)
+	}
+
+	params, err := handle.SwParams()
+	if err != nil {
+		t.Fatalf("SwParams failed. %s", err)
+	}
+	defer params.Free()
+
+	if err = params.SetStartThreshold(handle.Buffersize); err != nil {
+		t.Fatalf("SetStartThreshold failed. %s", err)
+	}
+	if err = params.SetAvailMin(handle.PeriodSize); err != nil {
+		t.Fatalf("SetAvailMin failed. %s", err)
+	}
+	if err = params.Apply(); err != nil {
+		t.Fatalf("Apply failed. %s", err)
+	}
+
+	current, err := handle.SwParams()
+	if err != nil {
+		t.Fatalf("SwParams failed. %s", err)
+	}
+	defer current.Free()
+
+	threshold, err := current.StartThreshold()
+	if err != nil {
+		t.Fatalf("StartThreshold failed. %s", err)
+	}
+	if threshold != handle.Buffersize {
+		t.Errorf("Start threshold %d, expected %d", threshold, handle.Buffersize)
+	}
+}
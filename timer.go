@@ -0,0 +1,387 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unsafe"
+)
+
+// Timer device class.
+type TimerClass C.snd_timer_class_t
+
+// Timer class constants.
+const (
+	// No timer, ends the enumeration
+	TimerClassNone = C.SND_TIMER_CLASS_NONE
+	// Slave timer driven by another timer
+	TimerClassSlave = C.SND_TIMER_CLASS_SLAVE
+	// Global timer, e.g. the system timer
+	TimerClassGlobal = C.SND_TIMER_CLASS_GLOBAL
+	// Timer of a sound card
+	TimerClassCard = C.SND_TIMER_CLASS_CARD
+	// Timer of a PCM stream
+	TimerClassPCM = C.SND_TIMER_CLASS_PCM
+)
+
+// Global timer devices.
+const (
+	// System timer, driven by the kernel jiffies
+	TimerGlobalSystem = C.SND_TIMER_GLOBAL_SYSTEM
+	// RTC timer
+	TimerGlobalRTC = C.SND_TIMER_GLOBAL_RTC
+	// HPET timer
+	TimerGlobalHPET = C.SND_TIMER_GLOBAL_HPET
+	// High resolution timer
+	TimerGlobalHRTimer = C.SND_TIMER_GLOBAL_HRTIMER
+)
+
+// SystemTimer is the name of the system timer. It is available without a
+// sound card. ListTimers reports the system timer with card -1, so its
+// TimerID.String differs from this name; match it by Class and Device.
+const SystemTimer = "hw:CLASS=1,SCLASS=0,CARD=0,DEV=0,SUBDEV=0"
+
+// String returns the timer class name.
+func (class TimerClass) String() string {
+	switch class {
+	case TimerClassNone:
+		return "none"
+	case TimerClassSlave:
+		return "slave"
+	case TimerClassGlobal:
+		return "global"
+	case TimerClassCard:
+		return "card"
+	case TimerClassPCM:
+		return "pcm"
+	}
+
+	return fmt.Sprintf("unknown (%d)", int(class))
+}
+
+// TimerID identifies a timer device.
+type TimerID struct {
+	// Timer class.
+	Class TimerClass
+	// Slave class, used by slave timers.
+	SlaveClass int
+	// Card index, -1 for global timers.
+	Card int
+	// Device index, e.g. TimerGlobalSystem for global timers.
+	Device int
+	// Subdevice index.
+	Subdevice int
+}
+
+// String returns the device name to pass to OpenTimer.
+func (id TimerID) String() string {
+	return fmt.Sprintf("hw:CLASS=%d,SCLASS=%d,CARD=%d,DEV=%d,SUBDEV=%d",
+		int(id.Class), id.SlaveClass, id.Card, id.Device, id.Subdevice)
+}
+
+// TimerInfo describes an opened timer.
+type TimerInfo struct {
+	// Timer identifier and name.
+	ID   string
+	Name string
+	// Card of the timer, -1 for global timers.
+	Card int
+	// Timer is a slave timer.
+	Slave bool
+	// Duration of one tick.
+	Resolution time.Duration
+}
+
+// TimerTick reports ticks elapsed since the previous one.
+type TimerTick struct {
+	// Duration of one tick.
+	Resolution time.Duration
+	// Number of ticks.
+	Ticks int
+}
+
+// Elapsed returns time elapsed by the ticks.
+func (tick TimerTick) Elapsed() time.Duration {
+	return tick.Resolution * time.Duration(tick.Ticks)
+}
+
+// Timer is an opened timer device.
+type Timer struct {
+	cTimer *C.snd_timer_t
+	name   string
+}
+
+// ListTimers returns identifiers of all timer devices.
+func ListTimers() ([]TimerID, error) {
+	var cQuery *C.snd_timer_query_t
+
+	cName := C.CString("hw")
+	defer C.free(unsafe.Pointer(cName))
+
+	err := C.snd_timer_query_open(&cQuery, cName, 0)
+	if err < 0 {
+		return nil, newDeviceError("open timer query", "hw", err)
+	}
+	defer C.snd_timer_query_close(cQuery)
+
+	var cID *C.snd_timer_id_t
+	err = C.snd_timer_id_malloc(&cID)
+	if err < 0 {
+		return nil, newDeviceError("allocate timer id", "hw", err)
+	}
+	defer C.snd_timer_id_free(cID)
+
+	C.snd_timer_id_set_class(cID, C.SND_TIMER_CLASS_NONE)
+
+	var ids []TimerID
+	for {
+		err = C.snd_timer_query_next_device(cQuery, cID)
+		if err < 0 {
+			return ids, newDeviceError("get next timer device", "hw", err)
+		}
+
+		class := TimerClass(C.snd_timer_id_get_class(cID))
+		if class < 0 {
+			break
+		}
+
+		ids = append(ids, TimerID{
+			Class:      class,
+			SlaveClass: int(C.snd_timer_id_get_sclass(cID)),
+			Card:       int(C.snd_timer_id_get_card(cID)),
+			Device:     int(C.snd_timer_id_get_device(cID)),
+			Subdevice:  int(C.snd_timer_id_get_subdevice(cID)),
+		})
+	}
+
+	return ids, nil
+}
+
+// OpenTimer opens timer device, e.g. SystemTimer or TimerID.String().
+func OpenTimer(name string) (*Timer, error) {
+	timer := &Timer{name: name}
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	err := C.snd_timer_open(&timer.cTimer, cName, 0)
+	if err < 0 {
+		return nil, timer.newError("open timer", err)
+	}
+
+	return timer, nil
+}
+
+// Close closes the timer.
+func (timer *Timer) Close() {
+	if timer.cTimer != nil {
+		C.snd_timer_close(timer.cTimer)
+		timer.cTimer = nil
+	}
+}
+
+// newError returns Error for the failed operation on the timer.
+func (timer *Timer) newError(op string, code C.int) error {
+	return newDeviceError(op, timer.name, code)
+}
+
+// Info returns information about the timer.
+func (timer *Timer) Info() (*TimerInfo, error) {
+	var cInfo *C.snd_timer_info_t
+	err := C.snd_timer_info_malloc(&cInfo)
+	if err < 0 {
+		return nil, timer.newError("allocate timer info", err)
+	}
+	defer C.snd_timer_info_free(cInfo)
+
+	err = C.snd_timer_info(timer.cTimer, cInfo)
+	if err < 0 {
+		return nil, timer.newError("get timer info", err)
+	}
+
+	return &TimerInfo{
+		ID:         C.GoString(C.snd_timer_info_get_id(cInfo)),
+		Name:       C.GoString(C.snd_timer_info_get_name(cInfo)),
+		Card:       int(C.snd_timer_info_get_card(cInfo)),
+		Slave:      C.snd_timer_info_is_slave(cInfo) != 0,
+		Resolution: time.Duration(C.snd_timer_info_get_resolution(cInfo)),
+	}, nil
+}
+
+// Resolution returns duration of one timer tick.
+func (timer *Timer) Resolution() (time.Duration, error) {
+	info, err := timer.Info()
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Resolution, nil
+}
+
+// SetTicks sets the timer period in ticks of Resolution. With autoStart
+// the timer restarts by itself after each period.
+func (timer *Timer) SetTicks(ticks int, autoStart bool) error {
+	var cParams *C.snd_timer_params_t
+	err := C.snd_timer_params_malloc(&cParams)
+	if err < 0 {
+		return timer.newError("allocate timer parameters", err)
+	}
+	defer C.snd_timer_params_free(cParams)
+
+	C.snd_timer_params_set_ticks(cParams, C.long(ticks))
+
+	err = C.snd_timer_params_set_auto_start(cParams, cBool(autoStart))
+	if err < 0 {
+		return timer.newError("set auto start", err)
+	}
+
+	err = C.snd_timer_params(timer.cTimer, cParams)
+	if err < 0 {
+		return timer.newError("set timer parameters", err)
+	}
+
+	return nil
+}
+
+// Start starts the timer.
+func (timer *Timer) Start() error {
+	err := C.snd_timer_start(timer.cTimer)
+	if err < 0 {
+		return timer.newError("start timer", err)
+	}
+
+	return nil
+}
+
+// Stop stops the timer.
+func (timer *Timer) Stop() error {
+	err := C.snd_timer_stop(timer.cTimer)
+	if err < 0 {
+		return timer.newError("stop timer", err)
+	}
+
+	return nil
+}
+
+// Continue continues the stopped timer.
+func (timer *Timer) Continue() error {
+	err := C.snd_timer_continue(timer.cTimer)
+	if err < 0 {
+		return timer.newError("continue timer", err)
+	}
+
+	return nil
+}
+
+// Read reads elapsed ticks into ticks and returns their number. It blocks
+// till the timer period elapses.
+func (timer *Timer) Read(ticks []TimerTick) (int, error) {
+	if len(ticks) == 0 {
+		return 0, nil
+	}
+
+	cReads := make([]C.snd_timer_read_t, len(ticks))
+	size := C.size_t(len(cReads)) * C.size_t(unsafe.Sizeof(cReads[0]))
+
+	n := C.snd_timer_read(timer.cTimer, unsafe.Pointer(&cReads[0]), size)
+	if n < 0 {
+		return 0, timer.newError("read timer", C.int(n))
+	}
+
+	count := int(n) / int(unsafe.Sizeof(cReads[0]))
+	for i := 0; i < count; i++ {
+		ticks[i] = goTimerTick(cReads[i])
+	}
+
+	return count, nil
+}
+
+// Ticks returns elapsed ticks of the started timer on the channel. The
+// channel is closed when ctx is done or reading the timer fails. The timer
+// must not be closed before the channel is.
+func (timer *Timer) Ticks(ctx context.Context) (<-chan TimerTick, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	fds, err := timer.pollFds()
+	if err != nil {
+		return nil, err
+	}
+
+	poller, err := NewPoller()
+	if err != nil {
+		return nil, err
+	}
+
+	ticks := make(chan TimerTick)
+	go func() {
+		defer close(ticks)
+		defer wakeOnDone(ctx, poller)()
+
+		var cReads [16]C.snd_timer_read_t
+		size := C.size_t(unsafe.Sizeof(cReads))
+
+		for {
+			err := poller.poll(fds, -1)
+			if err == ErrWoken {
+				if ctx.Err() != nil {
+					return
+				}
+				continue
+			}
+			if err != nil {
+				return
+			}
+
+			n := C.snd_timer_read(timer.cTimer, unsafe.Pointer(&cReads[0]), size)
+			if n == -C.EAGAIN {
+				continue
+			}
+			if n < 0 {
+				return
+			}
+
+			count := int(n) / int(unsafe.Sizeof(cReads[0]))
+			for i := 0; i < count; i++ {
+				select {
+				case ticks <- goTimerTick(cReads[i]):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return ticks, nil
+}
+
+// pollFds returns poll descriptors of the timer.
+func (timer *Timer) pollFds() ([]C.struct_pollfd, error) {
+	count := C.snd_timer_poll_descriptors_count(timer.cTimer)
+	if count < 0 {
+		return nil, timer.newError("get poll descriptors count", count)
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	cFds := make([]C.struct_pollfd, count)
+	filled := C.snd_timer_poll_descriptors(timer.cTimer, &cFds[0], C.uint(count))
+	if filled < 0 {
+		return nil, timer.newError("get poll descriptors", filled)
+	}
+
+	return cFds[:filled], nil
+}
+
+// goTimerTick converts the read timer record.
+func goTimerTick(cRead C.snd_timer_read_t) TimerTick {
+	return TimerTick{
+		Resolution: time.Duration(cRead.resolution),
+		Ticks:      int(cRead.ticks),
+	}
+}
@@ -0,0 +1,75 @@
+package alsa
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTimer(t *testing.T) {
+	ids, err := ListTimers()
+	if err != nil {
+		t.Fatalf("ListTimers failed. %s", err)
+	}
+	found := false
+	for _, id := range ids {
+		if id.Class == TimerClassGlobal && id.Device == TimerGlobalSystem {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("System timer not listed in %v", ids)
+	}
+
+	timer, err := OpenTimer(SystemTimer)
+	if err != nil {
+		t.Fatalf("OpenTimer failed. %s", err)
+	}
+	defer timer.Close()
+
+	resolution, err := timer.Resolution()
+	if err != nil {
+		t.Fatalf("Resolution failed. %s", err)
+	}
+	if resolution <= 0 {
+		t.Fatalf("Invalid resolution %v", resolution)
+	}
+
+	// Period of about 10ms.
+	ticks := int(10 * time.Millisecond / resolution)
+	if ticks < 1 {
+		ticks = 1
+	}
+	if err = timer.SetTicks(ticks, true); err != nil {
+		t.Fatalf("SetTicks failed. %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	elapsed, err := timer.Ticks(ctx)
+	if err != nil {
+		t.Fatalf("Ticks failed. %s", err)
+	}
+
+	if err = timer.Start(); err != nil {
+		t.Fatalf("Start failed. %s", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case tick := <-elapsed:
+			if tick.Ticks < 1 || tick.Resolution <= 0 {
+				t.Errorf("Unexpected tick %+v", tick)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timer tick not received")
+		}
+	}
+
+	if err = timer.Stop(); err != nil {
+		t.Fatalf("Stop failed. %s", err)
+	}
+	cancel()
+	for range elapsed {
+	}
+}
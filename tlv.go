@@ -0,0 +1,169 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+import "C"
+
+import (
+	"math"
+)
+
+// DBScale maps a range of raw control values to dB.
+type DBScale struct {
+	// Raw value range the scale applies to.
+	MinValue, MaxValue int64
+	// dB at MinValue and MaxValue. MinDB is -Inf when the lowest value
+	// mutes.
+	MinDB, MaxDB float64
+	// Lowest value mutes.
+	Mute bool
+	// Gain is linear between MinValue and MaxValue; otherwise dB is.
+	Linear bool
+}
+
+// ToDB converts raw value within the scale range to dB.
+func (scale DBScale) ToDB(value int64) float64 {
+	if value <= scale.MinValue {
+		if scale.Mute {
+			return math.Inf(-1)
+		}
+		return scale.MinDB
+	}
+	if value >= scale.MaxValue {
+		return scale.MaxDB
+	}
+
+	ratio := float64(value-scale.MinValue) / float64(scale.MaxValue-scale.MinValue)
+	if !scale.Linear {
+		return scale.MinDB + (scale.MaxDB-scale.MinDB)*ratio
+	}
+
+	minGain := math.Pow(10, scale.MinDB/20)
+	maxGain := math.Pow(10, scale.MaxDB/20)
+
+	return 20 * math.Log10(minGain+(maxGain-minGain)*ratio)
+}
+
+// ReadTLV returns raw TLV (type, length, value) data of the element, e.g.
+// its dB scale.
+func (ctl *Ctl) ReadTLV(id CtlElemID) ([]uint32, error) {
+	cID, err := ctl.cElemID(id)
+	if err != nil {
+		return nil, err
+	}
+	defer C.snd_ctl_elem_id_free(cID)
+
+	tlv := make([]uint32, 1024)
+	cErr := C.snd_ctl_elem_tlv_read(ctl.cCtl, cID, (*C.uint)(&tlv[0]), C.uint(len(tlv)*4))
+	if cErr < 0 {
+		return nil, ctl.newError("read TLV", cErr)
+	}
+
+	size := 2 + int(tlv[1]+3)/4
+	if size > len(tlv) {
+		size = len(tlv)
+	}
+
+	return tlv[:size], nil
+}
+
+// ElemDBScale returns dB scales of the element decoded from its TLV data.
+func (ctl *Ctl) ElemDBScale(id CtlElemID) ([]DBScale, error) {
+	info, err := ctl.ElemInfo(id)
+	if err != nil {
+		return nil, err
+	}
+	if !info.TLVReadable {
+		return nil, ctl.newError("read TLV", -C.ENXIO)
+	}
+
+	tlv, err := ctl.ReadTLV(info.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeDBScale(tlv, info.Min, info.Max)
+}
+
+// DecodeDBScale decodes dB scales from TLV data of an element with raw
+// value range min-max. Ranged TLVs give several scales; TLVs other than dB
+// scales are skipped.
+func DecodeDBScale(tlv []uint32, min, max int64) ([]DBScale, error) {
+	var scales []DBScale
+
+	for len(tlv) > 0 {
+		if len(tlv) < 2 {
+			return nil, errInvalidTLV
+		}
+		tlvType, size := tlv[0], int(tlv[1]+3)/4
+		if len(tlv)-2 < size {
+			return nil, errInvalidTLV
+		}
+		data := tlv[2 : 2+size]
+		tlv = tlv[2+size:]
+
+		switch tlvType {
+		case C.SND_CTL_TLVT_CONTAINER:
+			items, err := DecodeDBScale(data, min, max)
+			if err != nil {
+				return nil, err
+			}
+			scales = append(scales, items...)
+
+		case C.SND_CTL_TLVT_DB_SCALE:
+			if len(data) < 2 {
+				return nil, errInvalidTLV
+			}
+			minDB := int32(data[0])
+			step := int32(data[1] & 0xffff)
+			scales = append(scales, DBScale{
+				MinValue: min,
+				MaxValue: max,
+				MinDB:    tlvDB(minDB),
+				MaxDB:    tlvDB(minDB + step*int32(max-min)),
+				Mute:     data[1]&0x10000 != 0,
+			})
+
+		case C.SND_CTL_TLVT_DB_LINEAR, C.SND_CTL_TLVT_DB_MINMAX, C.SND_CTL_TLVT_DB_MINMAX_MUTE:
+			if len(data) < 2 {
+				return nil, errInvalidTLV
+			}
+			scale := DBScale{
+				MinValue: min,
+				MaxValue: max,
+				MinDB:    tlvDB(int32(data[0])),
+				MaxDB:    tlvDB(int32(data[1])),
+				Linear:   tlvType == C.SND_CTL_TLVT_DB_LINEAR,
+			}
+			scale.Mute = tlvType == C.SND_CTL_TLVT_DB_MINMAX_MUTE || math.IsInf(scale.MinDB, -1)
+			scales = append(scales, scale)
+
+		case C.SND_CTL_TLVT_DB_RANGE:
+			// Each range is min, max and a nested TLV item.
+			for len(data) > 0 {
+				if len(data) < 4 {
+					return nil, errInvalidTLV
+				}
+				itemSize := 2 + int(data[3]+3)/4
+				if len(data)-2 < itemSize {
+					return nil, errInvalidTLV
+				}
+				items, err := DecodeDBScale(data[2:2+itemSize], int64(data[0]), int64(data[1]))
+				if err != nil {
+					return nil, err
+				}
+				scales = append(scales, items...)
+				data = data[2+itemSize:]
+			}
+		}
+	}
+
+	return scales, nil
+}
+
+// errInvalidTLV is returned for malformed TLV data.
+var errInvalidTLV = newDeviceError("decode TLV", "", -C.EINVAL)
+
+// tlvDB converts TLV value in 0.01 dB units to dB.
+func tlvDB(value int32) float64 {
+	return fromCentiDB(C.long(value))
+}
@@ -0,0 +1,60 @@
+package alsa
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDecodeDBScale(t *testing.T) {
+	// DB_SCALE: -64.00 dB, 0.50 dB step, mute.
+	tlv := []uint32{1, 8, uint32(0xffffe700), 0x10000 | 50}
+	scales, err := DecodeDBScale(tlv, 0, 128)
+	if err != nil {
+		t.Fatalf("DecodeDBScale failed. %s", err)
+	}
+	if len(scales) != 1 {
+		t.Fatalf("Decoded %d scales, expected 1", len(scales))
+	}
+	scale := scales[0]
+	if scale.MinDB != -64 || scale.MaxDB != 0 || !scale.Mute || scale.Linear {
+		t.Errorf("Unexpected scale %+v", scale)
+	}
+	if dB := scale.ToDB(0); !math.IsInf(dB, -1) {
+		t.Errorf("Lowest value is %v dB, expected mute", dB)
+	}
+	if dB := scale.ToDB(64); dB != -32 {
+		t.Errorf("Value 64 is %v dB, expected -32 dB", dB)
+	}
+}
+
+func TestDecodeDBRange(t *testing.T) {
+	// DB_RANGE of MINMAX 0-10 for -30.00..-10.00 dB and DB_LINEAR 11-20
+	// for -10.00..0.00 dB.
+	tlv := []uint32{3, 48,
+		0, 10, 4, 8, uint32(0xfffff448), uint32(0xfffffc18),
+		11, 20, 2, 8, uint32(0xfffffc18), 0,
+	}
+	scales, err := DecodeDBScale(tlv, 0, 20)
+	if err != nil {
+		t.Fatalf("DecodeDBScale failed. %s", err)
+	}
+	if len(scales) != 2 {
+		t.Fatalf("Decoded %d scales, expected 2", len(scales))
+	}
+	if scales[0].MinValue != 0 || scales[0].MaxValue != 10 || scales[0].MinDB != -30 || scales[0].MaxDB != -10 {
+		t.Errorf("Unexpected first scale %+v", scales[0])
+	}
+	if scales[1].MinValue != 11 || scales[1].MaxValue != 20 || !scales[1].Linear || scales[1].MaxDB != 0 {
+		t.Errorf("Unexpected second scale %+v", scales[1])
+	}
+	if dB := scales[1].ToDB(20); dB != 0 {
+		t.Errorf("Highest value is %v dB, expected 0 dB", dB)
+	}
+}
+
+func TestDecodeInvalidTLV(t *testing.T) {
+	_, err := DecodeDBScale([]uint32{1, 8, 0}, 0, 10)
+	if err == nil {
+		t.Errorf("Truncated TLV decoded")
+	}
+}
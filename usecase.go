@@ -0,0 +1,297 @@
+package alsa
+
+// #include <alsa/asoundlib.h>
+// #include <alsa/use-case.h>
+import "C"
+
+import (
+	"errors"
+	"slices"
+	"unsafe"
+)
+
+// Standard use case verbs.
+const (
+	UseCaseVerbInactive  = "Inactive"
+	UseCaseVerbHiFi      = "HiFi"
+	UseCaseVerbVoice     = "Voice"
+	UseCaseVerbVoiceCall = "Voice Call"
+)
+
+// Standard use case devices.
+const (
+	UseCaseDevSpeaker    = "Speaker"
+	UseCaseDevHeadphones = "Headphones"
+	UseCaseDevHeadset    = "Headset"
+	UseCaseDevEarpiece   = "Earpiece"
+	UseCaseDevMic        = "Mic"
+	UseCaseDevLine       = "Line"
+	UseCaseDevHDMI       = "HDMI"
+	UseCaseDevSPDIF      = "SPDIF"
+)
+
+// UseCaseItem is a verb, device or modifier of a use case profile.
+type UseCaseItem struct {
+	Name    string
+	Comment string
+}
+
+// UseCaseManager routes a card by its UCM profile.
+type UseCaseManager struct {
+	cMgr *C.snd_use_case_mgr_t
+	card string
+}
+
+// OpenUseCaseManager loads UCM profile of the card, e.g. "hw:0" or the card
+// name.
+func OpenUseCaseManager(card string) (*UseCaseManager, error) {
+	ucm := &UseCaseManager{card: card}
+
+	cCard := C.CString(card)
+	defer C.free(unsafe.Pointer(cCard))
+
+	err := C.snd_use_case_mgr_open(&ucm.cMgr, cCard)
+	if err < 0 {
+		return nil, ucm.newError("open use case manager", err)
+	}
+
+	return ucm, nil
+}
+
+// Close closes the manager. Card routing is left as set.
+func (ucm *UseCaseManager) Close() {
+	if ucm.cMgr != nil {
+		C.snd_use_case_mgr_close(ucm.cMgr)
+		ucm.cMgr = nil
+	}
+}
+
+// newError returns Error for the failed operation on the card.
+func (ucm *UseCaseManager) newError(op string, code C.int) error {
+	return newDeviceError(op, ucm.card, code)
+}
+
+// Reload reloads the profile and resets the card to the inactive verb.
+func (ucm *UseCaseManager) Reload() error {
+	err := C.snd_use_case_mgr_reload(ucm.cMgr)
+	if err < 0 {
+		return ucm.newError("reload use case manager", err)
+	}
+
+	return nil
+}
+
+// Verbs returns verbs of the profile.
+func (ucm *UseCaseManager) Verbs() ([]UseCaseItem, error) {
+	return ucm.items("_verbs")
+}
+
+// Devices returns devices of the verb, or of the current verb when verb is
+// empty.
+func (ucm *UseCaseManager) Devices(verb string) ([]UseCaseItem, error) {
+	return ucm.items(useCaseIdentifier("_devices", verb))
+}
+
+// Modifiers returns modifiers of the verb, or of the current verb when
+// verb is empty.
+func (ucm *UseCaseManager) Modifiers(verb string) ([]UseCaseItem, error) {
+	return ucm.items(useCaseIdentifier("_modifiers", verb))
+}
+
+// EnabledDevices returns names of enabled devices of the current verb.
+func (ucm *UseCaseManager) EnabledDevices() ([]string, error) {
+	return ucm.list("_enadevs")
+}
+
+// EnabledModifiers returns names of enabled modifiers of the current verb.
+func (ucm *UseCaseManager) EnabledModifiers() ([]string, error) {
+	return ucm.list("_enamods")
+}
+
+// Verb returns the current verb, UseCaseVerbInactive when none is set.
+func (ucm *UseCaseManager) Verb() (string, error) {
+	verb, err := ucm.Get("_verb")
+	if errors.Is(err, ErrNoDevice) {
+		// No verb was set.
+		return UseCaseVerbInactive, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if verb == "" {
+		return UseCaseVerbInactive, nil
+	}
+
+	return verb, nil
+}
+
+// SetVerb switches the card to the verb, disabling all devices and
+// modifiers of the previous one.
+func (ucm *UseCaseManager) SetVerb(verb string) error {
+	return ucm.Set("_verb", verb)
+}
+
+// EnableDevice enables the device of the current verb.
+func (ucm *UseCaseManager) EnableDevice(device string) error {
+	return ucm.Set("_enadev", device)
+}
+
+// DisableDevice disables the device of the current verb.
+func (ucm *UseCaseManager) DisableDevice(device string) error {
+	return ucm.Set("_disdev", device)
+}
+
+// EnableModifier enables the modifier of the current verb.
+func (ucm *UseCaseManager) EnableModifier(modifier string) error {
+	return ucm.Set("_enamod", modifier)
+}
+
+// DisableModifier disables the modifier of the current verb.
+func (ucm *UseCaseManager) DisableModifier(modifier string) error {
+	return ucm.Set("_dismod", modifier)
+}
+
+// Value returns a value of the device or modifier of the current verb, e.g.
+// "PlaybackPCM", "CapturePCM" or "PlaybackVolume". Empty device returns the
+// verb value.
+func (ucm *UseCaseManager) Value(name, device string) (string, error) {
+	return ucm.Get(useCaseIdentifier(name, device))
+}
+
+// PCM returns the PCM device name of the device for the stream direction,
+// to pass to Handle.Open.
+func (ucm *UseCaseManager) PCM(device string, streamType StreamType) (string, error) {
+	if streamType == StreamTypeCapture {
+		return ucm.Value("CapturePCM", device)
+	}
+
+	return ucm.Value("PlaybackPCM", device)
+}
+
+// Get returns the value of the UCM identifier, e.g. "PlaybackPCM/Speaker".
+func (ucm *UseCaseManager) Get(identifier string) (string, error) {
+	cIdentifier := C.CString(identifier)
+	defer C.free(unsafe.Pointer(cIdentifier))
+
+	var cValue *C.char
+	err := C.snd_use_case_get(ucm.cMgr, cIdentifier, &cValue)
+	if err < 0 {
+		return "", ucm.newError("get "+identifier, err)
+	}
+	defer C.free(unsafe.Pointer(cValue))
+
+	return C.GoString(cValue), nil
+}
+
+// Set sets the UCM identifier, e.g. "_verb", to the value.
+func (ucm *UseCaseManager) Set(identifier, value string) error {
+	cIdentifier := C.CString(identifier)
+	defer C.free(unsafe.Pointer(cIdentifier))
+	cValue := C.CString(value)
+	defer C.free(unsafe.Pointer(cValue))
+
+	err := C.snd_use_case_set(ucm.cMgr, cIdentifier, cValue)
+	if err < 0 {
+		return ucm.newError("set "+identifier, err)
+	}
+
+	return nil
+}
+
+// items returns the list of name and comment pairs of the identifier.
+func (ucm *UseCaseManager) items(identifier string) ([]UseCaseItem, error) {
+	list, err := ucm.list(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]UseCaseItem, 0, len(list)/2)
+	for i := 0; i+1 < len(list); i += 2 {
+		items = append(items, UseCaseItem{Name: list[i], Comment: list[i+1]})
+	}
+
+	return items, nil
+}
+
+// list returns the list of strings of the identifier.
+func (ucm *UseCaseManager) list(identifier string) ([]string, error) {
+	cIdentifier := C.CString(identifier)
+	defer C.free(unsafe.Pointer(cIdentifier))
+
+	var cList **C.char
+	count := C.snd_use_case_get_list(ucm.cMgr, cIdentifier, &cList)
+	if count < 0 {
+		return nil, ucm.newError("get "+identifier, count)
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	defer C.snd_use_case_free_list(cList, count)
+
+	list := make([]string, count)
+	for i, cItem := range unsafe.Slice(cList, count) {
+		list[i] = C.GoString(cItem)
+	}
+
+	return list, nil
+}
+
+// useCaseIdentifier appends the optional verb or device to the identifier.
+func useCaseIdentifier(identifier, qualifier string) string {
+	if qualifier == "" {
+		return identifier
+	}
+
+	return identifier + "/" + qualifier
+}
+
+// OpenUseCase switches the card to the verb, enables the device and opens
+// the PCM device of the device profile. On failure the previous verb and
+// devices are restored.
+func (handle *Handle) OpenUseCase(ucm *UseCaseManager, verb, device string, streamType StreamType, mode int) error {
+	current, err := ucm.Verb()
+	if err != nil {
+		return err
+	}
+
+	enabled, err := ucm.EnabledDevices()
+	if err != nil {
+		return err
+	}
+
+	restore := func() {}
+	if current != verb {
+		if err = ucm.SetVerb(verb); err != nil {
+			return err
+		}
+		restore = func() { ucm.restore(current, enabled) }
+	} else if !slices.Contains(enabled, device) {
+		restore = func() { ucm.DisableDevice(device) }
+	}
+
+	if err = ucm.EnableDevice(device); err != nil {
+		restore()
+		return err
+	}
+
+	pcm, err := ucm.PCM(device, streamType)
+	if err == nil {
+		err = handle.Open(pcm, streamType, mode)
+	}
+	if err != nil {
+		restore()
+		return err
+	}
+
+	return nil
+}
+
+// restore switches back to the verb and enables its devices.
+func (ucm *UseCaseManager) restore(verb string, devices []string) {
+	if ucm.SetVerb(verb) != nil {
+		return
+	}
+	for _, device := range devices {
+		ucm.EnableDevice(device)
+	}
+}
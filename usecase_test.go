@@ -0,0 +1,83 @@
+package alsa
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUseCaseManager(t *testing.T) {
+	ucm, err := OpenUseCaseManager("hw:0")
+	if errors.Is(err, ErrNoDevice) {
+		t.Skipf("No UCM profile. %s", err)
+	}
+	if err != nil {
+		t.Fatalf("OpenUseCaseManager failed. %s", err)
+	}
+	defer ucm.Close()
+
+	verbs, err := ucm.Verbs()
+	if err != nil {
+		t.Fatalf("Verbs failed. %s", err)
+	}
+	if len(verbs) == 0 {
+		t.Skip("Profile has no verbs")
+	}
+
+	verb := verbs[0].Name
+	if err = ucm.SetVerb(verb); err != nil {
+		t.Fatalf("SetVerb failed. %s", err)
+	}
+	defer ucm.SetVerb(UseCaseVerbInactive)
+
+	current, err := ucm.Verb()
+	if err != nil {
+		t.Fatalf("Verb failed. %s", err)
+	}
+	if current != verb {
+		t.Errorf("Current verb %q, expected %q", current, verb)
+	}
+
+	devices, err := ucm.Devices("")
+	if err != nil {
+		t.Fatalf("Devices failed. %s", err)
+	}
+
+	for _, device := range devices {
+		if _, err := ucm.PCM(device.Name, StreamTypePlayback); err != nil {
+			continue
+		}
+
+		var handle Handle
+		err = handle.OpenUseCase(ucm, verb, device.Name, StreamTypePlayback, 0)
+		if err != nil {
+			t.Fatalf("OpenUseCase failed. %s", err)
+		}
+		handle.Close()
+
+		enabled, err := ucm.EnabledDevices()
+		if err != nil {
+			t.Fatalf("EnabledDevices failed. %s", err)
+		}
+		found := false
+		for _, name := range enabled {
+			found = found || name == device.Name
+		}
+		if !found {
+			t.Errorf("Device %q not enabled in %v", device.Name, enabled)
+		}
+
+		if err = ucm.DisableDevice(device.Name); err != nil {
+			t.Errorf("DisableDevice failed. %s", err)
+		}
+		return
+	}
+}
+
+func TestUseCaseIdentifier(t *testing.T) {
+	if id := useCaseIdentifier("PlaybackPCM", "Speaker"); id != "PlaybackPCM/Speaker" {
+		t.Errorf("Identifier %q", id)
+	}
+	if id := useCaseIdentifier("_devices", ""); id != "_devices" {
+		t.Errorf("Identifier %q", id)
+	}
+}